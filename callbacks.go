@@ -15,12 +15,12 @@ import (
 func initializeCallbacks(db *DB) *callbacks {
 	return &callbacks{
 		processors: map[string]*processor{
-			"create": {db: db},
-			"query":  {db: db},
-			"update": {db: db},
-			"delete": {db: db},
-			"row":    {db: db},
-			"raw":    {db: db},
+			"create": {db: db, name: "create"},
+			"query":  {db: db, name: "query"},
+			"update": {db: db, name: "update"},
+			"delete": {db: db, name: "delete"},
+			"row":    {db: db, name: "row"},
+			"raw":    {db: db, name: "raw"},
 		},
 	}
 }
@@ -31,6 +31,7 @@ type callbacks struct {
 }
 
 type processor struct {
+	name      string
 	db        *DB
 	fns       []func(*DB)
 	callbacks []*callback
@@ -86,6 +87,12 @@ func (p *processor) Execute(db *DB) {
 		}
 	}
 
+	if stmt.DB.StrictIdentifier && db.Error == nil {
+		if err := stmt.validateIdentifiers(); err != nil {
+			db.AddError(err)
+		}
+	}
+
 	if stmt.Dest != nil {
 		stmt.ReflectValue = reflect.ValueOf(stmt.Dest)
 		for stmt.ReflectValue.Kind() == reflect.Ptr {
@@ -96,18 +103,68 @@ func (p *processor) Execute(db *DB) {
 		}
 	}
 
-	for _, f := range p.fns {
-		f(db)
+	timeout := db.DefaultQueryTimeout
+	if p.name == "create" || p.name == "update" || p.name == "delete" {
+		timeout = db.DefaultWriteTimeout
+	} else if p.name == "row" {
+		// returned *sql.Row, *sql.Rows are read by callers after Execute returned, the context can't be canceled
+		// once they are closed, so they are not limited by timeout, use WithContext to limit them
+		timeout = 0
 	}
 
-	db.Logger.Trace(stmt.Context, curTime, func() (string, int64) {
-		return db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...), db.RowsAffected
-	}, db.Error)
+	if timeout > 0 && !stmt.DB.DryRun {
+		originalCtx, parentCtx := stmt.Context, stmt.Context
+		if parentCtx == nil {
+			parentCtx = context.Background()
+		}
+
+		ctx, cancel := context.WithTimeout(parentCtx, timeout)
+		stmt.Context = ctx
+
+		for _, f := range p.fns {
+			f(db)
+		}
+
+		if db.Error != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && parentCtx.Err() == nil {
+			db.Error = &QueryTimeoutError{Timeout: timeout, Elapsed: time.Since(curTime), SQL: stmt.SQL.String(), Err: db.Error}
+		}
+
+		cancel()
+		stmt.Context = originalCtx
+	} else {
+		for _, f := range p.fns {
+			f(db)
+		}
+	}
+
+	if tracer, ok := db.Logger.(logger.EventTracer); ok {
+		elapsed := time.Since(curTime)
+		if filter, ok := tracer.(logger.TraceEventFilter); !ok || filter.TraceEventEnabled(elapsed, db.Error) {
+			tracer.TraceEvent(stmt.Context, logger.TraceEvent{
+				Operation:  p.name,
+				Table:      stmt.Table,
+				SQL:        stmt.SQL.String(),
+				Vars:       stmt.Vars,
+				VarColumns: stmt.VarColumns(),
+				Rows:       db.RowsAffected,
+				Begin:      curTime,
+				Duration:   elapsed,
+				Error:      db.Error,
+				Caller:     utils.FileWithLineNum(),
+				Explainer:  db.Dialector.Explain,
+			})
+		}
+	} else {
+		db.Logger.Trace(stmt.Context, curTime, func() (string, int64) {
+			return db.Dialector.Explain(stmt.SQL.String(), stmt.Vars...), db.RowsAffected
+		}, db.Error)
+	}
 
 	if !stmt.DB.DryRun {
 		stmt.SQL.Reset()
 		stmt.Vars = nil
 		stmt.NamedVars = nil
+		stmt.varColumns = nil
 	}
 }
 
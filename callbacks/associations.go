@@ -139,10 +139,7 @@ func SaveAfterAssociations(db *gorm.DB) {
 						assignmentColumns = append(assignmentColumns, ref.ForeignKey.DBName)
 					}
 
-					db.Session(&gorm.Session{}).Clauses(clause.OnConflict{
-						Columns:   []clause.Column{{Name: rel.FieldSchema.PrioritizedPrimaryField.DBName}},
-						DoUpdates: clause.AssignmentColumns(assignmentColumns),
-					}).Create(elems.Interface())
+					saveAssociations(db, rel, elems.Interface(), assignmentColumns)
 				}
 			case reflect.Struct:
 				if _, zero := rel.Field.ValueOf(db.Statement.ReflectValue); !zero {
@@ -162,10 +159,7 @@ func SaveAfterAssociations(db *gorm.DB) {
 						assignmentColumns = append(assignmentColumns, ref.ForeignKey.DBName)
 					}
 
-					db.Session(&gorm.Session{}).Clauses(clause.OnConflict{
-						Columns:   []clause.Column{{Name: rel.FieldSchema.PrioritizedPrimaryField.DBName}},
-						DoUpdates: clause.AssignmentColumns(assignmentColumns),
-					}).Create(f.Interface())
+					saveAssociations(db, rel, f.Interface(), assignmentColumns)
 				}
 			}
 		}
@@ -221,10 +215,7 @@ func SaveAfterAssociations(db *gorm.DB) {
 					assignmentColumns = append(assignmentColumns, ref.ForeignKey.DBName)
 				}
 
-				db.Session(&gorm.Session{}).Clauses(clause.OnConflict{
-					Columns:   []clause.Column{{Name: rel.FieldSchema.PrioritizedPrimaryField.DBName}},
-					DoUpdates: clause.AssignmentColumns(assignmentColumns),
-				}).Create(elems.Interface())
+				saveAssociations(db, rel, elems.Interface(), assignmentColumns)
 			}
 		}
 
@@ -314,3 +305,42 @@ func saveAssociationCheck(db *gorm.DB, rel *schema.Relationship, selectColumns m
 
 	return false
 }
+
+// saveAssociations creates has one, has many associations and updates their assignment columns if they exist,
+// existing associations with version are updated one by one, so their versions could be checked
+func saveAssociations(db *gorm.DB, rel *schema.Relationship, values interface{}, assignmentColumns []string) {
+	if isVersioned(rel.FieldSchema) {
+		elems := reflect.ValueOf(values)
+		if elems.Kind() != reflect.Slice {
+			elems = reflect.Append(reflect.MakeSlice(reflect.SliceOf(elems.Type()), 0, 1), elems)
+		}
+
+		creates := reflect.MakeSlice(elems.Type(), 0, elems.Len())
+		for i := 0; i < elems.Len(); i++ {
+			if _, zero := rel.FieldSchema.PrioritizedPrimaryField.ValueOf(elems.Index(i)); zero {
+				creates = reflect.Append(creates, elems.Index(i))
+			} else if db.AddError(db.Session(&gorm.Session{}).Select(assignmentColumns).Save(elems.Index(i).Interface()).Error) != nil {
+				return
+			}
+		}
+
+		if creates.Len() == 0 {
+			return
+		}
+		values = creates.Interface()
+	}
+
+	db.Session(&gorm.Session{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: rel.FieldSchema.PrioritizedPrimaryField.DBName}},
+		DoUpdates: clause.AssignmentColumns(assignmentColumns),
+	}).Create(values)
+}
+
+func isVersioned(s *schema.Schema) bool {
+	for _, c := range s.UpdateClauses {
+		if _, ok := c.(gorm.VersionUpdateClause); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -21,6 +21,7 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	createCallback.Register("gorm:create", Create(config))
 	createCallback.Register("gorm:save_after_associations", SaveAfterAssociations)
 	createCallback.Register("gorm:after_create", AfterCreate)
+	createCallback.Register("gorm:after_commit_or_rollback", AfterCommitOrRollback)
 	createCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 
 	queryCallback := db.Callback().Query()
@@ -31,8 +32,10 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	deleteCallback := db.Callback().Delete()
 	deleteCallback.Match(enableTransaction).Register("gorm:begin_transaction", BeginTransaction)
 	deleteCallback.Register("gorm:before_delete", BeforeDelete)
+	deleteCallback.Register("gorm:delete_before_associations", DeleteBeforeAssociations)
 	deleteCallback.Register("gorm:delete", Delete)
 	deleteCallback.Register("gorm:after_delete", AfterDelete)
+	deleteCallback.Register("gorm:after_commit_or_rollback", AfterCommitOrRollback)
 	deleteCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 
 	updateCallback := db.Callback().Update()
@@ -42,7 +45,9 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	updateCallback.Register("gorm:save_before_associations", SaveBeforeAssociations)
 	updateCallback.Register("gorm:update", Update)
 	updateCallback.Register("gorm:save_after_associations", SaveAfterAssociations)
+	updateCallback.Register("gorm:restore_associations", RestoreAssociations)
 	updateCallback.Register("gorm:after_update", AfterUpdate)
+	updateCallback.Register("gorm:after_commit_or_rollback", AfterCommitOrRollback)
 	updateCallback.Match(enableTransaction).Register("gorm:commit_or_rollback_transaction", CommitOrRollbackTransaction)
 
 	db.Callback().Row().Register("gorm:raw", RowQuery)
@@ -92,6 +92,10 @@ func Create(config *Config) func(db *gorm.DB) {
 									db.AddError(err)
 								}
 							}
+
+							if db.RefreshDefaultValues && db.Error == nil {
+								RefreshDefaultValues(db)
+							}
 						}
 					} else {
 						db.AddError(err)
@@ -102,6 +106,53 @@ func Create(config *Config) func(db *gorm.DB) {
 	}
 }
 
+// RefreshDefaultValues re-select fields with database default values by primary keys after create, and set them to the created values
+func RefreshDefaultValues(db *gorm.DB) {
+	sch := db.Statement.Schema
+	if sch == nil || len(sch.PrimaryFields) == 0 {
+		return
+	}
+
+	columns := make([]string, 0, len(sch.FieldsWithDefaultDBValue)+len(sch.PrimaryFields))
+	columns = append(columns, sch.PrimaryFieldDBNames...)
+	for _, field := range sch.FieldsWithDefaultDBValue {
+		if !field.PrimaryKey && field.Readable {
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	if len(columns) == len(sch.PrimaryFields) {
+		return
+	}
+
+	_, queryValues := schema.GetIdentityFieldValuesMap(db.Statement.ReflectValue, sch.PrimaryFields)
+	if len(queryValues) == 0 {
+		return
+	}
+
+	// ORed conditions of records instead of IN with row values, which is not supported by databases like SQLite
+	exprs := make([]clause.Expression, len(queryValues))
+	for idx, values := range queryValues {
+		conds := make([]clause.Expression, len(values))
+		for i, value := range values {
+			conds[i] = clause.Eq{Column: sch.PrimaryFieldDBNames[i], Value: value}
+		}
+		exprs[idx] = clause.And(conds...)
+	}
+
+	rows, err := db.Session(&gorm.Session{}).Table(db.Statement.Table).Select(columns).Where(clause.Or(exprs...)).Rows()
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	defer rows.Close()
+
+	// scan with a session of the statement, so the rows won't be counted in db.RowsAffected
+	tx := db.Session(&gorm.Session{})
+	ScanReturning(tx, rows)
+	db.AddError(tx.Error)
+}
+
 func CreateWithReturning(db *gorm.DB) {
 	if db.Error == nil {
 		if db.Statement.Schema != nil && !db.Statement.Unscoped {
@@ -322,6 +373,28 @@ func ConvertToCreateValues(stmt *gorm.Statement) (values clause.Values) {
 			}
 			stmt.AddClause(onConflict)
 		}
+	} else if c, ok := stmt.Clauses["ON CONFLICT"]; ok && stmt.Schema != nil {
+		if onConflict, ok := c.Expression.(clause.OnConflict); ok && onConflict.UpdateAll {
+			columns := make([]string, 0, len(values.Columns))
+			for _, column := range values.Columns {
+				if field := stmt.Schema.LookUpField(column.Name); field != nil && field.Updatable {
+					if !field.PrimaryKey && field.AutoCreateTime == 0 {
+						columns = append(columns, column.Name)
+					}
+				}
+			}
+
+			if len(onConflict.Columns) == 0 && onConflict.OnConstraint == "" {
+				for _, field := range stmt.Schema.PrimaryFields {
+					onConflict.Columns = append(onConflict.Columns, clause.Column{Name: field.DBName})
+				}
+			}
+
+			onConflict.UpdateAll = false
+			onConflict.DoUpdates = append(clause.AssignmentColumns(columns), onConflict.DoUpdates...)
+			onConflict.DoNothing = len(onConflict.DoUpdates) == 0
+			stmt.AddClause(onConflict)
+		}
 	}
 
 	return values
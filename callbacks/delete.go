@@ -2,10 +2,12 @@ package callbacks
 
 import (
 	"reflect"
+	"sort"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
 )
 
 func BeforeDelete(db *gorm.DB) {
@@ -57,10 +59,20 @@ func Delete(db *gorm.DB) {
 			}
 
 			db.Statement.AddClauseIfNotExists(clause.From{})
-			db.Statement.Build("DELETE", "FROM", "WHERE")
+			db.Statement.Build("WITH", "DELETE", "FROM", "WHERE", "RETURNING")
 		}
 
 		if !db.DryRun {
+			if _, ok := db.Statement.Clauses["RETURNING"]; ok {
+				if rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...); err == nil {
+					defer rows.Close()
+					ScanReturning(db, rows)
+				} else {
+					db.AddError(err)
+				}
+				return
+			}
+
 			result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
 
 			if err == nil {
@@ -83,3 +95,106 @@ func AfterDelete(db *gorm.DB) {
 		})
 	}
 }
+
+// DeleteBeforeAssociations cascades deleting to has one, has many associations, and deletes join table records of many2many associations,
+// associations are cascaded if selected with Select or tagged with cascadeDelete, e.g: `gorm:"cascadeDelete"`
+// when soft deleting, has one, has many associations can't be soft deleted are skipped, use Unscoped to delete them permanently
+func DeleteBeforeAssociations(db *gorm.DB) {
+	if db.Error == nil && db.Statement.Schema != nil {
+		softDelete := !db.Statement.Unscoped && isSoftDeletable(db.Statement.Schema)
+		for _, rel := range cascadeRelationships(db.Statement) {
+			tx := db.Session(&gorm.Session{})
+			if db.Statement.Unscoped {
+				tx = tx.Unscoped()
+			}
+
+			switch rel.Type {
+			case schema.HasOne, schema.HasMany:
+				if softDelete && !isSoftDeletable(rel.FieldSchema) {
+					continue
+				}
+
+				modelValue := reflect.New(rel.FieldSchema.ModelType).Interface()
+				db.AddError(tx.Clauses(clause.Where{Exprs: rel.ToQueryConditions(db.Statement.ReflectValue)}).Delete(modelValue).Error)
+			case schema.Many2Many:
+				var (
+					queryConds     []clause.Expression
+					foreignFields  []*schema.Field
+					relForeignKeys []string
+				)
+
+				for _, ref := range rel.References {
+					if ref.OwnPrimaryKey {
+						foreignFields = append(foreignFields, ref.PrimaryKey)
+						relForeignKeys = append(relForeignKeys, ref.ForeignKey.DBName)
+					} else if ref.PrimaryValue != "" {
+						queryConds = append(queryConds, clause.Eq{Column: ref.ForeignKey.DBName, Value: ref.PrimaryValue})
+					}
+				}
+
+				_, foreignValues := schema.GetIdentityFieldValuesMap(db.Statement.ReflectValue, foreignFields)
+				column, values := schema.ToQueryValues(relForeignKeys, foreignValues)
+				queryConds = append(queryConds, clause.IN{Column: column, Values: values})
+
+				modelValue := reflect.New(rel.JoinTable.ModelType).Interface()
+				db.AddError(tx.Table(rel.JoinTable.Table).Clauses(clause.Where{Exprs: queryConds}).Delete(modelValue).Error)
+			}
+
+			if db.Error != nil {
+				return
+			}
+		}
+	}
+}
+
+// RestoreAssociations cascades restoring to soft deleted has one, has many associations, associations are chosen like DeleteBeforeAssociations
+func RestoreAssociations(db *gorm.DB) {
+	if _, ok := db.InstanceGet("gorm:restore"); ok && db.Error == nil && db.Statement.Schema != nil {
+		for _, rel := range cascadeRelationships(db.Statement) {
+			if rel.Type != schema.HasOne && rel.Type != schema.HasMany || !isSoftDeletable(rel.FieldSchema) {
+				continue
+			}
+
+			modelValue := reflect.New(rel.FieldSchema.ModelType).Interface()
+			if db.AddError(db.Session(&gorm.Session{}).Clauses(clause.Where{Exprs: rel.ToQueryConditions(db.Statement.ReflectValue)}).Restore(modelValue).Error) != nil {
+				return
+			}
+		}
+	}
+}
+
+// cascadeRelationships returns relationships should be cascaded, only works when deleting, restoring records with primary keys
+func cascadeRelationships(stmt *gorm.Statement) (rels []*schema.Relationship) {
+	if _, values := schema.GetIdentityFieldValuesMap(stmt.ReflectValue, stmt.Schema.PrimaryFields); len(values) == 0 {
+		return
+	}
+
+	selectColumns, _ := SelectAndOmitColumns(stmt, false, false)
+	for _, rel := range stmt.Schema.Relationships.Relations {
+		if rel.Type == schema.BelongsTo {
+			continue
+		}
+
+		if v, ok := selectColumns[rel.Name]; ok {
+			if v {
+				rels = append(rels, rel)
+			}
+		} else if v, ok := rel.Field.TagSettings["CASCADEDELETE"]; ok && utils.CheckTruth(v) {
+			rels = append(rels, rel)
+		}
+	}
+
+	sort.Slice(rels, func(i, j int) bool {
+		return rels[i].Name < rels[j].Name
+	})
+	return
+}
+
+func isSoftDeletable(s *schema.Schema) bool {
+	for _, c := range s.QueryClauses {
+		if _, ok := c.(gorm.SoftDeleteQueryClause); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,14 @@
 package callbacks
 
 import (
+	"database/sql"
+	"reflect"
 	"sort"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
 )
 
 // SelectAndOmitColumns get select and omit columns, select -> true, omit -> false
@@ -63,7 +67,8 @@ func SelectAndOmitColumns(stmt *gorm.Statement, requireCreate, requireUpdate boo
 
 // ConvertMapToValuesForCreate convert map to values
 func ConvertMapToValuesForCreate(stmt *gorm.Statement, mapValue map[string]interface{}) (values clause.Values) {
-	columns := make([]string, 0, len(mapValue))
+	values.Columns = make([]clause.Column, 0, len(mapValue))
+	values.Values = [][]interface{}{make([]interface{}, 0, len(mapValue))}
 	selectColumns, restricted := SelectAndOmitColumns(stmt, true, false)
 
 	var keys []string
@@ -79,7 +84,7 @@ func ConvertMapToValuesForCreate(stmt *gorm.Statement, mapValue map[string]inter
 		}
 
 		if v, ok := selectColumns[k]; (ok && v) || (!ok && !restricted) {
-			columns = append(columns, k)
+			values.Columns = append(values.Columns, clause.Column{Name: k})
 			values.Values[0] = append(values.Values[0], value)
 		}
 	}
@@ -125,3 +130,90 @@ func ConvertSliceOfMapToValuesForCreate(stmt *gorm.Statement, mapValues []map[st
 	}
 	return
 }
+
+// ScanReturning scan rows returned by RETURNING into the statement's reflect value,
+// slice elements are matched with primary keys, unmatched rows are appended to the slice
+func ScanReturning(db *gorm.DB, rows *sql.Rows) {
+	sch := db.Statement.Schema
+	if sch == nil {
+		gorm.Scan(rows, db, false)
+		return
+	}
+
+	var (
+		columns, _   = rows.Columns()
+		fields       = make([]*schema.Field, len(columns))
+		values       = make([]interface{}, len(columns))
+		reflectValue = db.Statement.ReflectValue
+		identityMap  map[string][]reflect.Value
+	)
+
+	for idx, column := range columns {
+		if field := sch.LookUpField(column); field != nil && field.Readable {
+			fields[idx] = field
+		} else {
+			values[idx] = &sql.RawBytes{}
+		}
+	}
+
+	setValues := func(rv reflect.Value) {
+		for idx, field := range fields {
+			if field != nil {
+				db.AddError(field.Set(rv, values[idx]))
+			}
+		}
+	}
+
+	if reflectValue.Kind() == reflect.Slice || reflectValue.Kind() == reflect.Array {
+		identityMap, _ = schema.GetIdentityFieldValuesMap(reflectValue, sch.PrimaryFields)
+	}
+
+	for rows.Next() {
+		for idx, field := range fields {
+			if field != nil {
+				values[idx] = reflect.New(reflect.PtrTo(field.IndirectFieldType)).Interface()
+			}
+		}
+
+		if err := rows.Scan(values...); err != nil {
+			db.AddError(err)
+			return
+		}
+		db.RowsAffected++
+
+		switch reflectValue.Kind() {
+		case reflect.Struct:
+			if db.RowsAffected == 1 {
+				setValues(reflectValue)
+			}
+		case reflect.Slice, reflect.Array:
+			elemType := reflectValue.Type().Elem()
+			isPtr := elemType.Kind() == reflect.Ptr
+			if isPtr {
+				elemType = elemType.Elem()
+			}
+
+			elem := reflect.New(elemType).Elem()
+			setValues(elem)
+
+			primaryValues := make([]interface{}, len(sch.PrimaryFields))
+			for idx, field := range sch.PrimaryFields {
+				primaryValues[idx], _ = field.ValueOf(elem)
+			}
+
+			if matched, ok := identityMap[utils.ToStringKey(primaryValues...)]; ok && len(primaryValues) > 0 {
+				for _, rv := range matched {
+					setValues(rv)
+				}
+			} else if reflectValue.Kind() == reflect.Slice && reflectValue.CanSet() {
+				if isPtr {
+					reflectValue.Set(reflect.Append(reflectValue, elem.Addr()))
+				} else {
+					reflectValue.Set(reflect.Append(reflectValue, elem))
+				}
+			}
+		}
+	}
+
+	db.AddError(rows.Err())
+}
@@ -133,7 +133,7 @@ func BuildQuerySQL(db *gorm.DB) {
 
 	db.Statement.AddClauseIfNotExists(clauseSelect)
 
-	db.Statement.Build("SELECT", "FROM", "WHERE", "GROUP BY", "ORDER BY", "LIMIT", "FOR")
+	db.Statement.Build("WITH", "SELECT", "FROM", "WHERE", "GROUP BY", "ORDER BY", "LIMIT", "FOR")
 }
 
 func Preload(db *gorm.DB) {
@@ -13,7 +13,12 @@ func RowQuery(db *gorm.DB) {
 		if _, ok := db.Get("rows"); ok {
 			db.Statement.Dest, db.Error = db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
 		} else {
-			db.Statement.Dest = db.Statement.ConnPool.QueryRowContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+			row := db.Statement.ConnPool.QueryRowContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
+			// deferred error of row could only be checked with Go 1.15+ before scanning
+			if r, ok := interface{}(row).(interface{ Err() error }); ok {
+				db.AddError(r.Err())
+			}
+			db.Statement.Dest = row
 		}
 	}
 }
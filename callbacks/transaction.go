@@ -1,9 +1,14 @@
 package callbacks
 
 import (
+	"context"
+
 	"gorm.io/gorm"
 )
 
+// transactionHooksKey marks the context of sessions passed to AfterCommit, AfterRollback hooks
+type transactionHooksKey struct{}
+
 func BeginTransaction(db *gorm.DB) {
 	if tx := db.Begin(); tx.Error == nil {
 		db.Statement.ConnPool = tx.Statement.ConnPool
@@ -23,3 +28,56 @@ func CommitOrRollbackTransaction(db *gorm.DB) {
 		db.Statement.ConnPool = db.ConnPool
 	}
 }
+
+// AfterCommitOrRollback queues AfterCommit, AfterRollback hooks to be called once the transaction finished,
+// calls them immediately if the statement isn't running in a transaction
+func AfterCommitOrRollback(db *gorm.DB) {
+	if db.Statement.Schema != nil && !inTransactionHooks(db) && (db.Statement.Schema.AfterCommit || db.Statement.Schema.AfterRollback) {
+		var values []interface{}
+		callMethod(db, func(value interface{}, tx *gorm.DB) bool {
+			_, isCommitter := value.(gorm.AfterCommitInterface)
+			_, isRollbacker := value.(gorm.AfterRollbackInterface)
+			if isCommitter || isRollbacker {
+				values = append(values, value)
+			}
+			return isCommitter || isRollbacker
+		})
+
+		// hooks run after the transaction finished, so use a session that doesn't use the transaction,
+		// mark its context to not queue transaction hooks for writes in them, otherwise they would be called recursively
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		tx := db.Session(&gorm.Session{Context: context.WithValue(ctx, transactionHooksKey{}, true)})
+		tx.Statement.ConnPool = db.ConnPool
+		logError := func(err error) {
+			if err != nil {
+				db.Logger.Error(db.Statement.Context, "failed to call transaction hook, got error %v", err)
+			}
+		}
+
+		inTransaction := db.InTransaction()
+		for _, value := range values {
+			if i, ok := value.(gorm.AfterCommitInterface); ok && db.Statement.Schema.AfterCommit && db.Error == nil {
+				if inTransaction {
+					db.AfterCommit(func() { logError(i.AfterCommit(tx)) })
+				} else {
+					logError(i.AfterCommit(tx))
+				}
+			}
+
+			if i, ok := value.(gorm.AfterRollbackInterface); ok && db.Statement.Schema.AfterRollback {
+				if inTransaction {
+					db.AfterRollback(func() { logError(i.AfterRollback(tx)) })
+				} else if db.Error != nil {
+					logError(i.AfterRollback(tx))
+				}
+			}
+		}
+	}
+}
+
+func inTransactionHooks(db *gorm.DB) bool {
+	return db.Statement.Context != nil && db.Statement.Context.Value(transactionHooksKey{}) != nil
+}
@@ -1,6 +1,7 @@
 package callbacks
 
 import (
+	"database/sql/driver"
 	"reflect"
 	"sort"
 
@@ -52,12 +53,6 @@ func BeforeUpdate(db *gorm.DB) {
 
 func Update(db *gorm.DB) {
 	if db.Error == nil {
-		if db.Statement.Schema != nil && !db.Statement.Unscoped {
-			for _, c := range db.Statement.Schema.UpdateClauses {
-				db.Statement.AddClause(c)
-			}
-		}
-
 		if db.Statement.SQL.String() == "" {
 			db.Statement.SQL.Grow(180)
 			db.Statement.AddClauseIfNotExists(clause.Update{})
@@ -66,7 +61,17 @@ func Update(db *gorm.DB) {
 			} else {
 				return
 			}
-			db.Statement.Build("UPDATE", "SET", "WHERE")
+		}
+
+		// update clauses are added after assignments, so they could change the SET clause
+		if db.Statement.Schema != nil && !db.Statement.Unscoped {
+			for _, c := range db.Statement.Schema.UpdateClauses {
+				db.Statement.AddClause(c)
+			}
+		}
+
+		if db.Statement.SQL.String() == "" {
+			db.Statement.Build("WITH", "UPDATE", "SET", "WHERE", "RETURNING")
 		}
 
 		if _, ok := db.Statement.Clauses["WHERE"]; !ok {
@@ -75,10 +80,22 @@ func Update(db *gorm.DB) {
 		}
 
 		if !db.DryRun {
+			if _, ok := db.Statement.Clauses["RETURNING"]; ok {
+				if rows, err := db.Statement.ConnPool.QueryContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...); err == nil {
+					defer rows.Close()
+					ScanReturning(db, rows)
+					checkVersion(db)
+				} else {
+					db.AddError(err)
+				}
+				return
+			}
+
 			result, err := db.Statement.ConnPool.ExecContext(db.Statement.Context, db.Statement.SQL.String(), db.Statement.Vars...)
 
 			if err == nil {
 				db.RowsAffected, _ = result.RowsAffected()
+				checkVersion(db)
 			} else {
 				db.AddError(err)
 			}
@@ -86,6 +103,27 @@ func Update(db *gorm.DB) {
 	}
 }
 
+// checkVersion returns ErrStaleObject if no record updated with current version, otherwise increase the version
+func checkVersion(db *gorm.DB) {
+	if v, ok := db.InstanceGet("gorm:version_field"); ok {
+		field := v.(*schema.Field)
+		if db.RowsAffected == 0 {
+			db.AddError(gorm.ErrStaleObject)
+		} else if value, isZero := field.ValueOf(db.Statement.ReflectValue); !isZero {
+			if valuer, ok := value.(driver.Valuer); ok {
+				value, _ = valuer.Value()
+			}
+
+			switch rv := reflect.Indirect(reflect.ValueOf(value)); rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				db.AddError(field.Set(db.Statement.ReflectValue, rv.Int()+1))
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				db.AddError(field.Set(db.Statement.ReflectValue, rv.Uint()+1))
+			}
+		}
+	}
+}
+
 func AfterUpdate(db *gorm.DB) {
 	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.UpdatingColumn && (db.Statement.Schema.AfterSave || db.Statement.Schema.AfterUpdate) {
 		callMethod(db, func(value interface{}, tx *gorm.DB) (called bool) {
@@ -153,7 +191,10 @@ func ConvertToAssignments(stmt *gorm.Statement) (set clause.Set) {
 					priamryKeyExprs = append(priamryKeyExprs, clause.And(exprs...))
 				}
 			}
-			stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.Or(priamryKeyExprs...)}})
+			if len(priamryKeyExprs) > 0 {
+				// wrap with And, so the primary key conditions won't be ORed with other conditions
+				stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.And(clause.Or(priamryKeyExprs...))}})
+			}
 		case reflect.Struct:
 			for _, field := range stmt.Schema.PrimaryFields {
 				if value, isZero := field.ValueOf(stmt.ReflectValue); !isZero {
@@ -2,12 +2,19 @@ package gorm
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/utils"
 )
 
+var (
+	tableRegexp       = regexp.MustCompile(`(?i)(?:.+? AS (\w+)\s*$|^\w+\s+(\w+)$)`)
+	columnRegexp      = regexp.MustCompile(`^\s*(?:(\w+)\.)?(\w+)\s*$`)
+	orderColumnRegexp = regexp.MustCompile(`(?i)^\s*(?:(\w+)\.)?(\w+)(?:\s+(ASC|DESC))?\s*$`)
+)
+
 // Model specify the model you would like to run db operations
 //    // update all users's name to `hello`
 //    db.Model(&User{}).Update("name", "hello")
@@ -42,9 +49,44 @@ func (db *DB) Clauses(conds ...clause.Expression) (tx *DB) {
 
 // Table specify the table you would like to run db operations
 // 设置表明 到 Statement 结构体里
-func (db *DB) Table(name string) (tx *DB) {
+//     db.Table("users").Find(&users)
+//     // subquery with alias, the alias will be used as current table
+//     db.Table("(?) AS u", db.Model(&User{}).Select("name", "age")).Where("u.age > ?", 18).Find(&results)
+func (db *DB) Table(name string, args ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if len(args) > 0 || strings.Contains(name, " ") {
+		tx.Statement.TableExpr = &clause.Expr{SQL: name, Vars: args}
+		if results := tableRegexp.FindStringSubmatch(name); len(results) == 3 {
+			if results[1] != "" {
+				tx.Statement.Table = results[1]
+			} else {
+				tx.Statement.Table = results[2]
+			}
+		}
+	} else {
+		tx.Statement.Table = name
+		tx.Statement.TableExpr = nil
+	}
+	return
+}
+
+// With specify common table expression with subquery, columns are optional
+//     db.With("adults", db.Model(&User{}).Where("age > ?", 18)).Table("adults").Find(&users)
+func (db *DB) With(name string, subQuery *DB, columns ...string) (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.AddClause(clause.With{CTEs: []clause.CTE{{
+		Name: name, Columns: columns, Expression: clause.Expr{SQL: "?", Vars: []interface{}{subQuery}},
+	}}})
+	return
+}
+
+// WithRecursive specify recursive common table expression with subquery
+//     db.WithRecursive("tree", db.Raw("SELECT id, parent_id FROM categories WHERE id = ? UNION ALL SELECT c.id, c.parent_id FROM categories c JOIN tree ON c.parent_id = tree.id", 1)).Table("tree").Find(&categories)
+func (db *DB) WithRecursive(name string, subQuery *DB, columns ...string) (tx *DB) {
 	tx = db.getInstance()
-	tx.Statement.Table = name
+	tx.Statement.AddClause(clause.With{Recursive: true, CTEs: []clause.CTE{{
+		Name: name, Columns: columns, Expression: clause.Expr{SQL: "?", Vars: []interface{}{subQuery}},
+	}}})
 	return
 }
 
@@ -80,6 +122,26 @@ func (db *DB) Select(query interface{}, args ...interface{}) (tx *DB) {
 			}
 		}
 	case string:
+		if tx.Config.StrictIdentifier {
+			tx.Statement.Selects = strings.Split(v, ",")
+			for _, arg := range args {
+				switch arg := arg.(type) {
+				case string:
+					tx.Statement.Selects = append(tx.Statement.Selects, arg)
+				case []string:
+					tx.Statement.Selects = append(tx.Statement.Selects, arg...)
+				default:
+					tx.AddError(fmt.Errorf("%w: select args %v %v, use clause.Expr for raw SQL", ErrInvalidIdentifier, query, args))
+					return
+				}
+			}
+
+			for idx, name := range tx.Statement.Selects {
+				tx.Statement.Selects[idx] = strings.TrimSpace(name)
+			}
+			return
+		}
+
 		fields := strings.FieldsFunc(v, utils.IsChar)
 
 		// normal field names
@@ -104,6 +166,8 @@ func (db *DB) Select(query interface{}, args ...interface{}) (tx *DB) {
 				Expression: clause.Expr{SQL: v, Vars: args},
 			})
 		}
+	case clause.Expr:
+		tx.Statement.AddClause(clause.Select{Expression: v})
 	default:
 		tx.AddError(fmt.Errorf("unsupported select args %v %v", query, args))
 	}
@@ -157,18 +221,45 @@ func (db *DB) Or(query interface{}, args ...interface{}) (tx *DB) {
 // Joins specify Joins conditions
 //     db.Joins("Account").Find(&user)
 //     db.Joins("JOIN emails ON emails.user_id = users.id AND emails.email = ?", "jinzhu@example.org").Find(&user)
-func (db *DB) Joins(query string, args ...interface{}) (tx *DB) {
+//     db.Joins(gorm.Expr("JOIN emails ON emails.user_id = users.id AND emails.email = ?", "jinzhu@example.org")).Find(&user)
+func (db *DB) Joins(query interface{}, args ...interface{}) (tx *DB) {
 	tx = db.getInstance()
 	if tx.Statement.Joins == nil {
 		tx.Statement.Joins = map[string][]interface{}{}
 	}
-	tx.Statement.Joins[query] = args
+
+	switch v := query.(type) {
+	case clause.Expr:
+		// expressions are not validated in strict identifier mode
+		tx.Statement.Joins[v.SQL] = v.Vars
+		tx.Statement.exprJoins = append(tx.Statement.exprJoins, v.SQL)
+	case string:
+		tx.Statement.Joins[v] = args
+	default:
+		tx.AddError(fmt.Errorf("unsupported joins args %v %v", query, args))
+	}
 	return
 }
 
 // Group specify the group method on the find
 func (db *DB) Group(name string) (tx *DB) {
 	tx = db.getInstance()
+	if tx.Config.StrictIdentifier {
+		var columns []clause.Column
+		for _, field := range strings.Split(name, ",") {
+			results := columnRegexp.FindStringSubmatch(field)
+			if len(results) != 3 {
+				tx.AddError(fmt.Errorf("%w: %v", ErrInvalidIdentifier, field))
+				return
+			}
+			columns = append(columns, clause.Column{Table: results[1], Name: results[2]})
+		}
+
+		tx.Statement.identifiers = append(tx.Statement.identifiers, columns...)
+		tx.Statement.AddClause(clause.GroupBy{Columns: columns})
+		return
+	}
+
 	tx.Statement.AddClause(clause.GroupBy{
 		Columns: []clause.Column{{Name: name}},
 	})
@@ -195,6 +286,31 @@ func (db *DB) Order(value interface{}) (tx *DB) {
 		tx.Statement.AddClause(clause.OrderBy{
 			Columns: []clause.OrderByColumn{v},
 		})
+	case clause.Expr:
+		tx.Statement.AddClause(clause.OrderBy{Expression: v})
+	case string:
+		if !tx.Config.StrictIdentifier {
+			tx.Statement.AddClause(clause.OrderBy{
+				Columns: []clause.OrderByColumn{{
+					Column: clause.Column{Name: v, Raw: true},
+				}},
+			})
+			return
+		}
+
+		var columns []clause.OrderByColumn
+		for _, field := range strings.Split(v, ",") {
+			results := orderColumnRegexp.FindStringSubmatch(field)
+			if len(results) != 4 {
+				tx.AddError(fmt.Errorf("%w: %v", ErrInvalidIdentifier, field))
+				return
+			}
+
+			column := clause.Column{Table: results[1], Name: results[2]}
+			tx.Statement.identifiers = append(tx.Statement.identifiers, column)
+			columns = append(columns, clause.OrderByColumn{Column: column, Desc: strings.EqualFold(results[3], "DESC")})
+		}
+		tx.Statement.AddClause(clause.OrderBy{Columns: columns})
 	default:
 		tx.Statement.AddClause(clause.OrderBy{
 			Columns: []clause.OrderByColumn{{
@@ -267,9 +383,18 @@ func (db *DB) Unscoped() (tx *DB) {
 	return
 }
 
+// OnlyDeleted only soft deleted records, the condition is built with the soft delete field of model
+//    db.OnlyDeleted().Find(&users)
+func (db *DB) OnlyDeleted() (tx *DB) {
+	tx = db.getInstance()
+	tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{softDeletedCondition{}}})
+	return
+}
+
 func (db *DB) Raw(sql string, values ...interface{}) (tx *DB) {
 	tx = db.getInstance()
 	tx.Statement.SQL = strings.Builder{}
-	clause.Expr{SQL: sql, Vars: values}.Build(tx.Statement)
+	tx.Statement.rawExpr = &clause.Expr{SQL: sql, Vars: values}
+	tx.Statement.rawExpr.Build(tx.Statement)
 	return
 }
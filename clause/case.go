@@ -0,0 +1,30 @@
+package clause
+
+// Case case expression, e.g: CASE WHEN `id` = 1 THEN 'a' WHEN `id` = 2 THEN 'b' ELSE `name` END
+type Case struct {
+	WhenThens []WhenThen
+	Else      interface{}
+}
+
+// WhenThen WHEN THEN branch of case expression
+type WhenThen struct {
+	When Expression
+	Then interface{}
+}
+
+// Build build case expression
+func (c Case) Build(builder Builder) {
+	builder.WriteString("CASE")
+	for _, whenThen := range c.WhenThens {
+		builder.WriteString(" WHEN ")
+		whenThen.When.Build(builder)
+		builder.WriteString(" THEN ")
+		builder.AddVar(builder, whenThen.Then)
+	}
+
+	if c.Else != nil {
+		builder.WriteString(" ELSE ")
+		builder.AddVar(builder, c.Else)
+	}
+	builder.WriteString(" END")
+}
@@ -0,0 +1,47 @@
+package clause_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestCase(t *testing.T) {
+	results := []struct {
+		Clauses []clause.Interface
+		Result  string
+		Vars    []interface{}
+	}{
+		{
+			[]clause.Interface{
+				clause.Update{},
+				clause.Set([]clause.Assignment{{clause.Column{Name: "name"}, clause.Case{
+					WhenThens: []clause.WhenThen{
+						{When: clause.Eq{Column: clause.PrimaryColumn, Value: 1}, Then: "jinzhu"},
+						{When: clause.Eq{Column: clause.PrimaryColumn, Value: 2}, Then: "jinzhu2"},
+					},
+					Else: clause.Column{Name: "name"},
+				}}}),
+			},
+			"UPDATE `users` SET `name`=CASE WHEN `users`.`id` = ? THEN ? WHEN `users`.`id` = ? THEN ? ELSE `name` END", []interface{}{1, "jinzhu", 2, "jinzhu2"},
+		},
+		{
+			[]clause.Interface{
+				clause.Update{},
+				clause.Set([]clause.Assignment{{clause.Column{Name: "age"}, clause.Case{
+					WhenThens: []clause.WhenThen{
+						{When: clause.And(clause.Eq{Column: "id", Value: 1}, clause.Eq{Column: "name", Value: "jinzhu"}), Then: 18},
+					},
+				}}}),
+			},
+			"UPDATE `users` SET `age`=CASE WHEN (`id` = ? AND `name` = ?) THEN ? END", []interface{}{1, "jinzhu", 18},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
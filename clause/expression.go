@@ -1,6 +1,7 @@
 package clause
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"reflect"
 )
@@ -75,8 +76,14 @@ func (in IN) Build(builder Builder) {
 	case 0:
 		builder.WriteString(" IN (NULL)")
 	case 1:
-		builder.WriteString(" = ")
-		builder.AddVar(builder, in.Values...)
+		if isSubQuery(in.Values[0]) {
+			builder.WriteString(" IN (")
+			builder.AddVar(builder, in.Values...)
+			builder.WriteByte(')')
+		} else {
+			builder.WriteString(" = ")
+			builder.AddVar(builder, in.Values...)
+		}
 	default:
 		builder.WriteString(" IN (")
 		builder.AddVar(builder, in.Values...)
@@ -89,8 +96,14 @@ func (in IN) NegationBuild(builder Builder) {
 	case 0:
 	case 1:
 		builder.WriteQuoted(in.Column)
-		builder.WriteString(" <> ")
-		builder.AddVar(builder, in.Values...)
+		if isSubQuery(in.Values[0]) {
+			builder.WriteString(" NOT IN (")
+			builder.AddVar(builder, in.Values...)
+			builder.WriteByte(')')
+		} else {
+			builder.WriteString(" <> ")
+			builder.AddVar(builder, in.Values...)
+		}
 	default:
 		builder.WriteQuoted(in.Column)
 		builder.WriteString(" NOT IN (")
@@ -99,6 +112,21 @@ func (in IN) NegationBuild(builder Builder) {
 	}
 }
 
+// subQuery *gorm.DB used as value, it is matched by methods as clause can't refer to gorm.DB
+type subQuery interface {
+	Row() *sql.Row
+	Rows() (*sql.Rows, error)
+}
+
+// isSubQuery whether the value will be built as a subquery, e.g: expression or *gorm.DB
+func isSubQuery(value interface{}) bool {
+	switch value.(type) {
+	case Expression, subQuery:
+		return true
+	}
+	return false
+}
+
 // Eq equal to for where
 type Eq struct {
 	Column interface{}
@@ -1,10 +1,12 @@
 package clause
 
 type OnConflict struct {
-	Columns   []Column
-	Where     Where
-	DoNothing bool
-	DoUpdates Set
+	Columns      []Column
+	OnConstraint string
+	Where        Where
+	DoNothing    bool
+	DoUpdates    Set
+	UpdateAll    bool // update all updatable non-primary columns being inserted, expanded by the create callbacks
 }
 
 func (OnConflict) Name() string {
@@ -13,7 +15,11 @@ func (OnConflict) Name() string {
 
 // Build build onConflict clause
 func (onConflict OnConflict) Build(builder Builder) {
-	if len(onConflict.Columns) > 0 {
+	if onConflict.OnConstraint != "" {
+		builder.WriteString("ON CONSTRAINT ")
+		builder.WriteString(onConflict.OnConstraint)
+		builder.WriteByte(' ')
+	} else if len(onConflict.Columns) > 0 {
 		builder.WriteByte('(')
 		for idx, column := range onConflict.Columns {
 			if idx > 0 {
@@ -42,3 +48,46 @@ func (onConflict OnConflict) Build(builder Builder) {
 func (onConflict OnConflict) MergeClause(clause *Clause) {
 	clause.Expression = onConflict
 }
+
+// Excluded column of the row proposed for insertion, e.g: `excluded`.`name`
+func Excluded(column string) Column {
+	return Column{Table: "excluded", Name: column}
+}
+
+// BuildOnDuplicateKeyUpdate build onConflict clause as `ON DUPLICATE KEY UPDATE`, excluded columns are built as `VALUES(column)`,
+// dialects like MySQL could register it with Config.ClauseBuilders["ON CONFLICT"]
+func BuildOnDuplicateKeyUpdate(c Clause, builder Builder) {
+	onConflict, ok := c.Expression.(OnConflict)
+	if !ok {
+		c.Build(builder)
+		return
+	}
+
+	builder.WriteString("ON DUPLICATE KEY UPDATE ")
+	if onConflict.DoNothing || len(onConflict.DoUpdates) == 0 {
+		column := Column{Name: PrimaryKey}
+		if len(onConflict.Columns) > 0 {
+			column = onConflict.Columns[0]
+		}
+		builder.WriteQuoted(column)
+		builder.WriteByte('=')
+		builder.WriteQuoted(column)
+		return
+	}
+
+	for idx, assignment := range onConflict.DoUpdates {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+
+		builder.WriteQuoted(assignment.Column)
+		builder.WriteByte('=')
+		if column, ok := assignment.Value.(Column); ok && column.Table == "excluded" {
+			builder.WriteString("VALUES(")
+			builder.WriteQuoted(Column{Name: column.Name})
+			builder.WriteByte(')')
+		} else {
+			builder.AddVar(builder, assignment.Value)
+		}
+	}
+}
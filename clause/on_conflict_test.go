@@ -0,0 +1,103 @@
+package clause_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestOnConflict(t *testing.T) {
+	results := []struct {
+		Clauses []clause.Interface
+		Result  string
+		Vars    []interface{}
+	}{
+		{
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "name"}}, Values: [][]interface{}{{"jinzhu"}}}, clause.OnConflict{DoNothing: true}},
+			"INSERT INTO `users` (`name`) VALUES (?) ON CONFLICT DO NOTHING", []interface{}{"jinzhu"},
+		},
+		{
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "name"}}, Values: [][]interface{}{{"jinzhu"}}}, clause.OnConflict{
+				Columns: []clause.Column{{Name: "id"}}, DoUpdates: clause.AssignmentColumns([]string{"name"}),
+			}},
+			"INSERT INTO `users` (`name`) VALUES (?) ON CONFLICT (`id`) DO UPDATE SET `name`=`excluded`.`name`", []interface{}{"jinzhu"},
+		},
+		{
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "name"}}, Values: [][]interface{}{{"jinzhu"}}}, clause.OnConflict{
+				OnConstraint: "users_name_key", DoUpdates: clause.Set{{Column: clause.Column{Name: "name"}, Value: clause.Excluded("name")}},
+			}},
+			"INSERT INTO `users` (`name`) VALUES (?) ON CONFLICT ON CONSTRAINT users_name_key DO UPDATE SET `name`=`excluded`.`name`", []interface{}{"jinzhu"},
+		},
+		{
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "name"}}, Values: [][]interface{}{{"jinzhu"}}}, clause.OnConflict{
+				Columns: []clause.Column{{Name: "id"}}, Where: clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "age", Value: 18}}}, DoUpdates: clause.Assignments(map[string]interface{}{"name": "jinzhu2"}),
+			}},
+			"INSERT INTO `users` (`name`) VALUES (?) ON CONFLICT (`id`) WHERE `age` = ? DO UPDATE SET `name`=?", []interface{}{"jinzhu", 18, "jinzhu2"},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
+
+func TestOnConflictClauseBuilders(t *testing.T) {
+	results := []struct {
+		Builders map[string]clause.ClauseBuilder
+		Clauses  []clause.Interface
+		Result   string
+		Vars     []interface{}
+	}{
+		{
+			map[string]clause.ClauseBuilder{"ON CONFLICT": clause.BuildOnDuplicateKeyUpdate},
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "id"}, {Name: "name"}}, Values: [][]interface{}{{1, "jinzhu"}}}, clause.OnConflict{
+				DoUpdates: append(clause.AssignmentColumns([]string{"name"}), clause.Assignment{Column: clause.Column{Name: "age"}, Value: 18}),
+			}},
+			"INSERT INTO `users` (`id`,`name`) VALUES (?,?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`age`=?", []interface{}{1, "jinzhu", 18},
+		},
+		{
+			map[string]clause.ClauseBuilder{"ON CONFLICT": clause.BuildOnDuplicateKeyUpdate},
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "name"}}, Values: [][]interface{}{{"jinzhu"}}}, clause.OnConflict{DoNothing: true}},
+			"INSERT INTO `users` (`name`) VALUES (?) ON DUPLICATE KEY UPDATE `id`=`id`", []interface{}{"jinzhu"},
+		},
+		{
+			gorm.MergeClauseBuilders(),
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "id"}, {Name: "name"}}, Values: [][]interface{}{{1, "jinzhu"}, {2, "jinzhu2"}}}, clause.OnConflict{
+				DoUpdates: clause.AssignmentColumns([]string{"name"}),
+			}},
+			"MERGE INTO `users` USING (VALUES (?,?),(?,?)) AS `excluded` (`id`,`name`) ON `users`.`id` = `excluded`.`id` WHEN MATCHED THEN UPDATE SET `name`=`excluded`.`name` WHEN NOT MATCHED THEN INSERT (`id`,`name`) VALUES (`excluded`.`id`,`excluded`.`name`);", []interface{}{1, "jinzhu", 2, "jinzhu2"},
+		},
+		{
+			gorm.MergeClauseBuilders(),
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "name"}}, Values: [][]interface{}{{"jinzhu"}}}, clause.OnConflict{
+				Columns: []clause.Column{{Name: "name"}}, DoNothing: true,
+			}},
+			"MERGE INTO `users` USING (VALUES (?)) AS `excluded` (`name`) ON `users`.`name` = `excluded`.`name` WHEN NOT MATCHED THEN INSERT (`name`) VALUES (`excluded`.`name`);", []interface{}{"jinzhu"},
+		},
+		{
+			gorm.MergeClauseBuilders(),
+			[]clause.Interface{clause.Insert{}, clause.Values{Columns: []clause.Column{{Name: "name"}}, Values: [][]interface{}{{"jinzhu"}}}},
+			"INSERT INTO `users` (`name`) VALUES (?)", []interface{}{"jinzhu"},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			for name, builder := range result.Builders {
+				db.ClauseBuilders[name] = builder
+			}
+
+			defer func() {
+				for name := range result.Builders {
+					delete(db.ClauseBuilders, name)
+				}
+			}()
+
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
@@ -7,7 +7,8 @@ type OrderByColumn struct {
 }
 
 type OrderBy struct {
-	Columns []OrderByColumn
+	Columns    []OrderByColumn
+	Expression Expression
 }
 
 // Name where clause name
@@ -17,6 +18,13 @@ func (orderBy OrderBy) Name() string {
 
 // Build build where clause
 func (orderBy OrderBy) Build(builder Builder) {
+	if orderBy.Expression != nil {
+		orderBy.Expression.Build(builder)
+		if len(orderBy.Columns) > 0 {
+			builder.WriteByte(',')
+		}
+	}
+
 	for idx, column := range orderBy.Columns {
 		if idx > 0 {
 			builder.WriteByte(',')
@@ -40,10 +48,32 @@ func (orderBy OrderBy) MergeClause(clause *Clause) {
 			}
 		}
 
-		copiedColumns := make([]OrderByColumn, len(v.Columns))
-		copy(copiedColumns, v.Columns)
-		orderBy.Columns = append(copiedColumns, orderBy.Columns...)
+		if v.Expression != nil || orderBy.Expression != nil {
+			// keep orders of expressions and columns by merging them into a list
+			list, ok := v.Expression.(orderByList)
+			if !ok || len(v.Columns) > 0 {
+				list = orderByList{v}
+			}
+
+			orderBy = OrderBy{Expression: append(append(orderByList{}, list...), orderBy)}
+		} else {
+			copiedColumns := make([]OrderByColumn, len(v.Columns))
+			copy(copiedColumns, v.Columns)
+			orderBy.Columns = append(copiedColumns, orderBy.Columns...)
+		}
 	}
 
 	clause.Expression = orderBy
 }
+
+// orderByList merged order by clauses with expressions
+type orderByList []OrderBy
+
+func (list orderByList) Build(builder Builder) {
+	for idx, orderBy := range list {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		orderBy.Build(builder)
+	}
+}
@@ -39,6 +39,48 @@ func TestOrderBy(t *testing.T) {
 			},
 			"SELECT * FROM `users` ORDER BY `name`", nil,
 		},
+		{
+			[]clause.Interface{
+				clause.Select{}, clause.From{}, clause.OrderBy{
+					Expression: clause.Expr{SQL: "FIELD(id, ?)", Vars: []interface{}{[]int{1, 2, 3}}},
+				},
+			},
+			"SELECT * FROM `users` ORDER BY FIELD(id, (?,?,?))", []interface{}{1, 2, 3},
+		},
+		{
+			[]clause.Interface{
+				clause.Select{}, clause.From{}, clause.OrderBy{
+					Expression: clause.Expr{SQL: "FIELD(id, ?)", Vars: []interface{}{[]int{1, 2}}},
+				}, clause.OrderBy{
+					Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "name"}}},
+				},
+			},
+			"SELECT * FROM `users` ORDER BY FIELD(id, (?,?)),`name`", []interface{}{1, 2},
+		},
+		{
+			[]clause.Interface{
+				clause.Select{}, clause.From{}, clause.OrderBy{
+					Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "name"}}},
+				}, clause.OrderBy{
+					Expression: clause.Expr{SQL: "age DESC"},
+				}, clause.OrderBy{
+					Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "id"}, Desc: true}},
+				}, clause.OrderBy{
+					Expression: clause.Expr{SQL: "FIELD(role, ?)", Vars: []interface{}{"admin"}},
+				},
+			},
+			"SELECT * FROM `users` ORDER BY `name`,age DESC,`id` DESC,FIELD(role, ?)", []interface{}{"admin"},
+		},
+		{
+			[]clause.Interface{
+				clause.Select{}, clause.From{}, clause.OrderBy{
+					Expression: clause.Expr{SQL: "age DESC"},
+				}, clause.OrderBy{
+					Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "name"}, Reorder: true}},
+				},
+			},
+			"SELECT * FROM `users` ORDER BY `name`", nil,
+		},
 	}
 
 	for idx, result := range results {
@@ -9,8 +9,13 @@ func (returning Returning) Name() string {
 	return "RETURNING"
 }
 
-// Build build where clause
+// Build build where clause, returns all columns if no columns specified
 func (returning Returning) Build(builder Builder) {
+	if len(returning.Columns) == 0 {
+		builder.WriteByte('*')
+		return
+	}
+
 	for idx, column := range returning.Columns {
 		if idx > 0 {
 			builder.WriteByte(',')
@@ -54,7 +54,7 @@ func Assignments(values map[string]interface{}) Set {
 func AssignmentColumns(values []string) Set {
 	assignments := make([]Assignment, len(values))
 	for idx, value := range values {
-		assignments[idx] = Assignment{Column: Column{Name: value}, Value: Column{Table: "excluded", Name: value}}
+		assignments[idx] = Assignment{Column: Column{Name: value}, Value: Excluded(value)}
 	}
 	return assignments
 }
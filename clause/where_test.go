@@ -8,6 +8,7 @@ import (
 )
 
 func TestWhere(t *testing.T) {
+	location := &struct{ X, Y int }{1, 2}
 	results := []struct {
 		Clauses []clause.Interface
 		Result  string
@@ -59,6 +60,18 @@ func TestWhere(t *testing.T) {
 			}},
 			"SELECT * FROM `users` WHERE (`age` = ? OR `name` <> ?)", []interface{}{18, "jinzhu"},
 		},
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.Where{
+				Exprs: []clause.Expression{clause.IN{Column: "id", Values: []interface{}{clause.Expr{SQL: "SELECT `user_id` FROM `pets` WHERE `name` = ?", Vars: []interface{}{"pet"}}}}, clause.Not(clause.IN{Column: "role", Values: []interface{}{clause.Expr{SQL: "SELECT `name` FROM `roles`"}}}), clause.IN{Column: "age", Values: []interface{}{18}}},
+			}},
+			"SELECT * FROM `users` WHERE `id` IN (SELECT `user_id` FROM `pets` WHERE `name` = ?) AND `role` NOT IN (SELECT `name` FROM `roles`) AND `age` = ?", []interface{}{"pet", 18},
+		},
+		{
+			[]clause.Interface{clause.Select{}, clause.From{}, clause.Where{
+				Exprs: []clause.Expression{clause.IN{Column: "location", Values: []interface{}{location}}, clause.Not(clause.IN{Column: "origin", Values: []interface{}{location}})},
+			}},
+			"SELECT * FROM `users` WHERE `location` = ? AND `origin` <> ?", []interface{}{location, location},
+		},
 	}
 
 	for idx, result := range results {
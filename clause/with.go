@@ -1,4 +1,56 @@
 package clause
 
+// With with clause, common table expressions
 type With struct {
+	Recursive bool
+	CTEs      []CTE
+}
+
+// CTE common table expression
+type CTE struct {
+	Name       string
+	Columns    []string
+	Expression Expression
+}
+
+// Name with clause name
+func (with With) Name() string {
+	return "WITH"
+}
+
+// Build build with clause
+func (with With) Build(builder Builder) {
+	if with.Recursive {
+		builder.WriteString("RECURSIVE ")
+	}
+
+	for idx, cte := range with.CTEs {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+
+		builder.WriteQuoted(cte.Name)
+		if len(cte.Columns) > 0 {
+			builder.WriteByte(' ')
+			builder.WriteQuoted(cte.Columns)
+		}
+
+		builder.WriteString(" AS (")
+		if cte.Expression != nil {
+			cte.Expression.Build(builder)
+		}
+		builder.WriteByte(')')
+	}
+}
+
+// MergeClause merge with clauses
+func (with With) MergeClause(clause *Clause) {
+	if v, ok := clause.Expression.(With); ok {
+		copiedCTEs := make([]CTE, len(v.CTEs))
+		copy(copiedCTEs, v.CTEs)
+		with.CTEs = append(copiedCTEs, with.CTEs...)
+		with.Recursive = with.Recursive || v.Recursive
+	}
+
+	clause.Expression = with
 }
@@ -2,6 +2,8 @@ package gorm
 
 import (
 	"errors"
+	"fmt"
+	"time"
 )
 
 var (
@@ -11,12 +13,18 @@ var (
 	ErrInvalidSQL = errors.New("invalid SQL")
 	// ErrInvalidTransaction invalid transaction when you are trying to `Commit` or `Rollback`
 	ErrInvalidTransaction = errors.New("no valid transaction")
+	// ErrMissingTransaction no transaction for PropagationMandatory
+	ErrMissingTransaction = errors.New("transaction required")
+	// ErrExistingTransaction existing transaction for PropagationNever
+	ErrExistingTransaction = errors.New("transaction not allowed")
 	// ErrUnaddressable unaddressable value
 	ErrUnaddressable = errors.New("using unaddressable value")
 	// ErrNotImplemented not implemented
 	ErrNotImplemented = errors.New("not implemented")
 	// ErrMissingWhereClause missing where clause
 	ErrMissingWhereClause = errors.New("WHERE conditions required")
+	// ErrInvalidData unsupported data
+	ErrInvalidData = errors.New("unsupported data")
 	// ErrUnsupportedRelation unsupported relations
 	ErrUnsupportedRelation = errors.New("unsupported relations")
 	// ErrPtrStructSupported only ptr of struct supported
@@ -29,4 +37,68 @@ var (
 	ErrUnsupportedDriver = errors.New("unsupported driver")
 	// ErrRegistered registered
 	ErrRegistered = errors.New("registered")
+	// ErrStaleObject stale object, the record has been updated by others since it was loaded
+	ErrStaleObject = errors.New("stale object")
+	// ErrDestructiveMigration migrating column will narrow it and might lose data, e.g: shrink size, set not null
+	ErrDestructiveMigration = errors.New("destructive migration")
+	// ErrInvalidIdentifier identifier is not a known column or association of the statement's schema, happens in strict identifier mode
+	ErrInvalidIdentifier = errors.New("invalid identifier")
+	// ErrDuplicatedKey unique constraint or primary key violated
+	ErrDuplicatedKey = errors.New("duplicated key not allowed")
+	// ErrForeignKeyViolated foreign key constraint violated
+	ErrForeignKeyViolated = errors.New("violates foreign key constraint")
+	// ErrCheckConstraintViolated check constraint violated
+	ErrCheckConstraintViolated = errors.New("violates check constraint")
+	// ErrSerializationFailure transaction can't be serialized, could be retried
+	ErrSerializationFailure = errors.New("serialization failure")
+	// ErrDeadlock deadlock detected, could be retried
+	ErrDeadlock = errors.New("deadlock detected")
+	// ErrLockTimeout lock wait timeout exceeded
+	ErrLockTimeout = errors.New("lock wait timeout")
+	// ErrMissingSoftDeleteField model doesn't have soft delete field, happens when OnlyDeleted, Restore soft deleted records
+	ErrMissingSoftDeleteField = errors.New("missing soft delete field")
+	// ErrQueryTimeout statement exceeded the query timeout of Session or Config, caller's cancellation won't be reported as it
+	ErrQueryTimeout = errors.New("query timeout")
 )
+
+// TranslatedError driver error translated by dialector's ErrorTranslator, both of them are reachable with errors.Is and errors.As
+type TranslatedError struct {
+	Err       error
+	DriverErr error
+}
+
+func (e *TranslatedError) Error() string {
+	return e.Err.Error() + ": " + e.DriverErr.Error()
+}
+
+// Unwrap returns the driver error
+func (e *TranslatedError) Unwrap() error {
+	return e.DriverErr
+}
+
+// Is returns true if target is the translated error
+func (e *TranslatedError) Is(target error) bool {
+	return errors.Is(e.Err, target)
+}
+
+// QueryTimeoutError statement canceled by the query timeout of Session or Config, it is reachable with errors.Is(err, ErrQueryTimeout)
+type QueryTimeoutError struct {
+	Timeout time.Duration
+	Elapsed time.Duration
+	SQL     string
+	Err     error
+}
+
+func (e *QueryTimeoutError) Error() string {
+	return fmt.Sprintf("%v of %v exceeded, elapsed %v: %v", ErrQueryTimeout, e.Timeout, e.Elapsed, e.Err)
+}
+
+// Unwrap returns the error returned by the driver
+func (e *QueryTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is ErrQueryTimeout
+func (e *QueryTimeoutError) Is(target error) bool {
+	return target == ErrQueryTimeout
+}
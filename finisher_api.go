@@ -2,17 +2,72 @@ package gorm
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"math/rand"
 	"reflect"
 	"strings"
+	"time"
 
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
-// Create insert the value into database
+// Create insert the value into database, slices are inserted in batches if CreateBatchSize configured or exceeded dialector's max placeholders
 func (db *DB) Create(value interface{}) (tx *DB) {
+	return db.CreateInBatches(value, db.CreateBatchSize)
+}
+
+// CreateInBatches insert the value in batches of batchSize in one transaction, batch size is also capped by dialector's max placeholders
+func (db *DB) CreateInBatches(value interface{}, batchSize int) (tx *DB) {
 	tx = db.getInstance()
+	reflectValue := reflect.Indirect(reflect.ValueOf(value))
+
+	if reflectValue.Kind() == reflect.Slice || reflectValue.Kind() == reflect.Array {
+		if limiter, ok := tx.Dialector.(PlaceholderLimiter); ok && limiter.MaxPlaceholders() > 0 && tx.Statement.Parse(value) == nil {
+			maxSize := limiter.MaxPlaceholders()
+			if columns := len(tx.Statement.Schema.DBNames); columns > 0 {
+				maxSize = maxSize / columns
+			}
+
+			if maxSize <= 0 {
+				maxSize = 1
+			}
+
+			if batchSize <= 0 || batchSize > maxSize {
+				batchSize = maxSize
+			}
+		}
+
+		if batchSize > 0 && reflectValue.Len() > batchSize && (reflectValue.Kind() == reflect.Slice || reflectValue.CanAddr()) {
+			var rowsAffected int64
+			tx.AddError(tx.Transaction(func(tx *DB) error {
+				for i := 0; i < reflectValue.Len(); i += batchSize {
+					ends := i + batchSize
+					if ends > reflectValue.Len() {
+						ends = reflectValue.Len()
+					}
+
+					// batch shares elements with value, so primary keys, default values are backfilled to value
+					batch := reflect.New(reflect.SliceOf(reflectValue.Type().Elem()))
+					batch.Elem().Set(reflectValue.Slice(i, ends))
+
+					subtx := tx.getInstance()
+					subtx.Statement.Dest = batch.Interface()
+					subtx.callbacks.Create().Execute(subtx)
+					if subtx.Error != nil {
+						return subtx.Error
+					}
+					rowsAffected += subtx.RowsAffected
+				}
+				return nil
+			}))
+			tx.RowsAffected = rowsAffected
+			return
+		}
+	}
+
 	tx.Statement.Dest = value
 	tx.callbacks.Create().Execute(tx)
 	return
@@ -26,6 +81,28 @@ func (db *DB) Save(value interface{}) (tx *DB) {
 	reflectValue := reflect.Indirect(reflect.ValueOf(value))
 	switch reflectValue.Kind() {
 	case reflect.Slice, reflect.Array:
+		if err := tx.Statement.Parse(value); err == nil && versionField(tx.Statement.Schema) != nil {
+			// save records with version one by one, the upserting statement can't check their versions
+			var rowsAffected int64
+			tx.AddError(db.Transaction(func(tx *DB) error {
+				for i := 0; i < reflectValue.Len(); i++ {
+					elem := reflectValue.Index(i)
+					if elem.Kind() != reflect.Ptr && elem.CanAddr() {
+						elem = elem.Addr()
+					}
+
+					result := tx.Save(elem.Interface())
+					if result.Error != nil {
+						return result.Error
+					}
+					rowsAffected += result.RowsAffected
+				}
+				return nil
+			}))
+			tx.RowsAffected = rowsAffected
+			return
+		}
+
 		tx.Statement.UpdatingColumn = true
 		tx.callbacks.Create().Execute(tx)
 	case reflect.Struct:
@@ -249,6 +326,208 @@ func (db *DB) UpdateColumns(values interface{}) (tx *DB) {
 	return
 }
 
+// UpdateBatch update records with their own values, every batch is updated in one statement, records are matched with primary keys,
+// non-primary updatable fields are updated if no columns specified, hooks won't be called like UpdateColumns
+//    db.Model(&Product{}).UpdateBatch(products, "price")
+func (db *DB) UpdateBatch(values interface{}, columns ...string) (tx *DB) {
+	return db.UpdateInBatches(values, nil, columns...)
+}
+
+// UpdateInBatches works like UpdateBatch, calls fc with the result of every batch, e.g: tx.RowsAffected of the batch,
+// batches are updated in a transaction, returning error from fc rollbacks it
+//    db.Model(&Product{}).UpdateInBatches(products, func(tx *gorm.DB, batch int) error {
+//      fmt.Println(batch, tx.RowsAffected)
+//      return nil
+//    }, "price")
+func (db *DB) UpdateInBatches(values interface{}, fc func(tx *DB, batch int) error, columns ...string) (tx *DB) {
+	tx = db.getInstance()
+	reflectValue := reflect.Indirect(reflect.ValueOf(values))
+	if reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array {
+		tx.AddError(fmt.Errorf("%w: UpdateBatch requires slice, got %T", ErrInvalidData, values))
+		return
+	}
+
+	if tx.Statement.Model == nil {
+		tx.Statement.Model = values
+	}
+
+	if err := tx.Statement.Parse(tx.Statement.Model); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	sch := tx.Statement.Schema
+	if len(sch.PrimaryFields) == 0 {
+		tx.AddError(ErrorPrimaryKeyRequired)
+		return
+	}
+
+	var fields []*schema.Field
+	if len(columns) == 0 {
+		for _, field := range sch.Fields {
+			if field.DBName != "" && field.Updatable && !field.PrimaryKey {
+				fields = append(fields, field)
+			}
+		}
+	} else {
+		for _, column := range columns {
+			if field := sch.LookUpField(column); field != nil && field.DBName != "" {
+				fields = append(fields, field)
+			} else {
+				tx.AddError(fmt.Errorf("%w: %v", ErrInvalidIdentifier, column))
+				return
+			}
+		}
+	}
+
+	var rows []reflect.Value
+	for i := 0; i < reflectValue.Len(); i++ {
+		row := reflect.Indirect(reflectValue.Index(i))
+		for _, field := range sch.PrimaryFields {
+			if _, isZero := field.ValueOf(row); isZero {
+				tx.AddError(ErrorPrimaryKeyRequired)
+				return
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	batchSize := db.UpdateBatchSize
+	if limiter, ok := tx.Dialector.(PlaceholderLimiter); ok && limiter.MaxPlaceholders() > 0 {
+		// every row binds primary keys in WHEN clauses of fields and WHERE clause, and binds values of fields
+		maxSize := limiter.MaxPlaceholders() / (len(sch.PrimaryFields)*(len(fields)+1) + len(fields))
+		if maxSize <= 0 {
+			maxSize = 1
+		}
+
+		if batchSize <= 0 || batchSize > maxSize {
+			batchSize = maxSize
+		}
+	}
+
+	if batchSize <= 0 || batchSize > len(rows) {
+		batchSize = len(rows)
+	}
+
+	var version *schema.Field
+	if !tx.Statement.Unscoped {
+		version = versionField(sch)
+	}
+
+	updateBatch := func(tx *DB, rows []reflect.Value) *DB {
+		var (
+			set        = make(clause.Set, 0, len(fields)+1)
+			whenConds  = make([]clause.Expression, len(rows))
+			whereConds = make([]clause.Expression, len(rows))
+		)
+
+		for idx, row := range rows {
+			conds := make([]clause.Expression, len(sch.PrimaryFields))
+			for i, field := range sch.PrimaryFields {
+				value, _ := field.ValueOf(row)
+				conds[i] = clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value}
+			}
+			whenConds[idx] = clause.And(conds...)
+
+			// check the current version of every row like updating a single record
+			if version != nil {
+				if value, isZero := version.ValueOf(row); !isZero {
+					conds = append(conds, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: version.DBName}, Value: value})
+				}
+			}
+			whereConds[idx] = clause.And(conds...)
+		}
+
+		for _, field := range fields {
+			caseExpr := clause.Case{Else: clause.Column{Name: field.DBName}}
+			for idx, row := range rows {
+				value, _ := field.ValueOf(row)
+				caseExpr.WhenThens = append(caseExpr.WhenThens, clause.WhenThen{When: whenConds[idx], Then: value})
+			}
+			set = append(set, clause.Assignment{Column: clause.Column{Name: field.DBName}, Value: caseExpr})
+		}
+
+		if version != nil {
+			column := clause.Column{Name: version.DBName}
+			set = append(set, clause.Assignment{Column: column, Value: clause.Expr{SQL: tx.Statement.Quote(column) + "+1"}})
+		}
+
+		tx.Statement.AddClauseIfNotExists(clause.Update{})
+		tx.Statement.AddClause(set)
+		// ORed conditions of rows instead of IN with row values, which is not supported by databases like SQLite
+		tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.And(clause.Or(whereConds...))}})
+		tx.Statement.Build("WITH", "UPDATE", "SET", "WHERE")
+
+		tx.Statement.Dest = values
+		tx.Statement.UpdatingColumn = true
+		tx.callbacks.Update().Execute(tx)
+		return tx
+	}
+
+	var rowsAffected int64
+	updateBatches := func(tx *DB) error {
+		for batch, i := 1, 0; i < len(rows); batch, i = batch+1, i+batchSize {
+			ends := i + batchSize
+			if ends > len(rows) {
+				ends = len(rows)
+			}
+
+			subtx := updateBatch(tx.getInstance(), rows[i:ends])
+			if subtx.Error != nil {
+				return subtx.Error
+			}
+
+			// rows with outdated versions are not matched
+			if version != nil && subtx.RowsAffected < int64(ends-i) {
+				return ErrStaleObject
+			}
+
+			if fc != nil {
+				if err := fc(subtx, batch); err != nil {
+					return err
+				}
+			}
+			rowsAffected += subtx.RowsAffected
+		}
+		return nil
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+
+	result := tx.Session(&Session{WithConditions: true})
+	if len(rows) <= batchSize && version == nil && fc == nil {
+		tx.AddError(updateBatches(result))
+	} else {
+		tx.AddError(result.Transaction(updateBatches))
+	}
+	tx.RowsAffected = rowsAffected
+
+	if tx.Error == nil && version != nil {
+		// write the increased versions back to the records
+		for _, row := range rows {
+			if !row.CanAddr() {
+				continue
+			}
+
+			if value, isZero := version.ValueOf(row); !isZero {
+				if valuer, ok := value.(driver.Valuer); ok {
+					value, _ = valuer.Value()
+				}
+
+				switch rv := reflect.Indirect(reflect.ValueOf(value)); rv.Kind() {
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+					tx.AddError(version.Set(row, rv.Int()+1))
+				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+					tx.AddError(version.Set(row, rv.Uint()+1))
+				}
+			}
+		}
+	}
+	return
+}
+
 // Delete delete value match given conditions, if the value has primary key, then will including the primary key as condition
 func (db *DB) Delete(value interface{}, conds ...interface{}) (tx *DB) {
 	tx = db.getInstance()
@@ -260,6 +539,47 @@ func (db *DB) Delete(value interface{}, conds ...interface{}) (tx *DB) {
 	return
 }
 
+// ForceDelete permanently delete value match given conditions, including soft deleted records
+func (db *DB) ForceDelete(value interface{}, conds ...interface{}) (tx *DB) {
+	return db.Unscoped().Delete(value, conds...)
+}
+
+// Restore restore soft deleted value match given conditions, if the value has primary key, then will including the primary key as condition
+func (db *DB) Restore(value interface{}, conds ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if err := tx.Statement.Parse(value); err != nil {
+		tx.AddError(err)
+		return
+	}
+
+	field := softDeleteField(tx.Statement.Schema)
+	if field == nil {
+		tx.AddError(ErrMissingSoftDeleteField)
+		return
+	}
+
+	values := map[string]interface{}{}
+	for _, assignment := range softDeleteAssignments(tx.Statement, field, false) {
+		values[assignment.Column.Name] = assignment.Value
+	}
+
+	if len(conds) > 0 {
+		tx.Statement.AddClause(clause.Where{Exprs: tx.Statement.BuildCondition(conds[0], conds[1:]...)})
+	}
+	tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{softDeletedCondition{}}})
+	if len(tx.Statement.Selects) > 0 {
+		// associations to restore are selected, select soft delete columns also
+		for column := range values {
+			tx.Statement.Selects = append(tx.Statement.Selects, column)
+		}
+	}
+	tx.Statement.Model = value
+	tx.Statement.Dest = values
+	tx.InstanceSet("gorm:restore", true)
+	tx.callbacks.Update().Execute(tx)
+	return
+}
+
 func (db *DB) Count(count *int64) (tx *DB) {
 	tx = db.getInstance()
 	if tx.Statement.Model == nil {
@@ -343,23 +663,92 @@ func (db *DB) ScanRows(rows *sql.Rows, dest interface{}) error {
 	return tx.Error
 }
 
+// Connection uses a single connection of the pool to run fc, the connection is released once fc returned,
+// so connection scoped states like session variables, temporary tables and locks are kept for the block
+func (db *DB) Connection(fc func(tx *DB) error) (err error) {
+	if db.Error != nil {
+		return db.Error
+	}
+
+	tx := db.Session(&Session{WithConditions: true, Context: db.Statement.Context})
+	if tx.InTransaction() {
+		// transaction already uses a single connection
+		return fc(tx)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	conn, err := sqlDB.Conn(tx.Statement.Context)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if preparedStmt, ok := tx.Statement.ConnPool.(*PreparedStmtDB); ok {
+		// statements prepared with the pool might be executed on other connections, prepare them with the pinned connection
+		stmtDB := &PreparedStmtDB{ConnPool: conn, Stmts: map[string]*sql.Stmt{}, MaxSize: preparedStmt.MaxSize}
+		defer stmtDB.Close()
+		tx.Statement.ConnPool = stmtDB
+	} else {
+		tx.Statement.ConnPool = conn
+	}
+
+	return fc(tx)
+}
+
 // Transaction start a transaction as a block, return error will rollback, otherwise to commit.
+// Nested transactions are run in savepoints, or joined to the outer transaction if DisableNestedTransaction enabled
 func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err error) {
+	propagation := PropagationNested
+	if db.DisableNestedTransaction {
+		propagation = PropagationRequired
+	}
+	return db.TransactionWithPropagation(propagation, fc, opts...)
+}
+
+// TransactionWithPropagation start a transaction as a block like Transaction, propagation decides how it works with the current transaction
+func (db *DB) TransactionWithPropagation(propagation Propagation, fc func(tx *DB) error, opts ...*sql.TxOptions) (err error) {
 	panicked := true
+	committer, inTransaction := db.Statement.ConnPool.(TxCommitter)
+	inTransaction = inTransaction && committer != nil
+
+	switch {
+	case propagation == PropagationMandatory && !inTransaction:
+		return ErrMissingTransaction
+	case propagation == PropagationNever && inTransaction:
+		return ErrExistingTransaction
+	case propagation == PropagationNever, (propagation == PropagationRequired || propagation == PropagationMandatory) && inTransaction:
+		// join the current transaction or run without transaction
+		return fc(db.Session(&Session{WithConditions: true}))
+	case propagation == PropagationNested && inTransaction:
+		savePoint := nextSavePointName(db.Statement.ConnPool)
+		if err = db.SavePoint(savePoint).Error; err != nil {
+			return err
+		}
 
-	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
-		// nested transaction
-		db.SavePoint(fmt.Sprintf("sp%p", fc))
 		defer func() {
 			// Make sure to rollback when panic, Block error or Commit error
 			if panicked || err != nil {
-				db.RollbackTo(fmt.Sprintf("sp%p", fc))
+				db.RollbackTo(savePoint)
 			}
 		}()
 
 		err = fc(db.Session(&Session{WithConditions: true}))
-	} else {
-		tx := db.Begin(opts...)
+	default:
+		tx := db
+		if propagation == PropagationRequiresNew && inTransaction {
+			// begin the new transaction with another connection of the pool, the session clones the statement,
+			// so the outer transaction's conn pool won't be changed
+			tx = db.Session(&Session{WithConditions: true, Context: db.Statement.Context})
+			tx.Statement.ConnPool = db.ConnPool
+		}
+		tx = tx.Begin(opts...)
+		if tx.Error != nil {
+			return tx.Error
+		}
 
 		defer func() {
 			// Make sure to rollback when panic, Block error or Commit error
@@ -379,6 +768,72 @@ func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err er
 	return
 }
 
+// RetryPolicy retry policy of TransactionWithRetry
+type RetryPolicy struct {
+	// MaxRetries max retry times, default is 3
+	MaxRetries int
+	// MinBackoff backoff before the first retry, it doubles for every retry, default is 10ms
+	MinBackoff time.Duration
+	// MaxBackoff max backoff between retries, default is 1s
+	MaxBackoff time.Duration
+	// Retryable returns true if the transaction should be retried, default retries ErrSerializationFailure, ErrDeadlock, ErrLockTimeout
+	Retryable func(error) bool
+}
+
+// TransactionWithRetry start a transaction as a block like Transaction, the block will be retried in a new transaction with exponential backoff if it failed with retryable errors, policy could be nil.
+// Nested transactions won't be retried as they are savepoints of the outer transaction, which should be retried instead
+func (db *DB) TransactionWithRetry(fc func(tx *DB) error, policy *RetryPolicy, opts ...*sql.TxOptions) (err error) {
+	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
+		return db.Transaction(fc, opts...)
+	}
+
+	retryPolicy := RetryPolicy{MaxRetries: 3, MinBackoff: 10 * time.Millisecond, MaxBackoff: time.Second, Retryable: isRetryableError}
+	if policy != nil {
+		if policy.MaxRetries != 0 {
+			retryPolicy.MaxRetries = policy.MaxRetries
+		}
+		if policy.MinBackoff != 0 {
+			retryPolicy.MinBackoff = policy.MinBackoff
+		}
+		if policy.MaxBackoff != 0 {
+			retryPolicy.MaxBackoff = policy.MaxBackoff
+		}
+		if policy.Retryable != nil {
+			retryPolicy.Retryable = policy.Retryable
+		}
+	}
+
+	for retries := 0; ; retries++ {
+		if err = db.Transaction(fc, opts...); err == nil || retries >= retryPolicy.MaxRetries || !retryPolicy.Retryable(err) {
+			return
+		}
+
+		backoff := retryPolicy.MinBackoff << retries
+		if backoff > retryPolicy.MaxBackoff || backoff <= 0 {
+			backoff = retryPolicy.MaxBackoff
+		}
+
+		// sleep random duration between [backoff/2, backoff) to avoid retrying at the same time with other conflicting transactions
+		backoff = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+
+		if ctx := db.Statement.Context; ctx != nil {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		} else {
+			time.Sleep(backoff)
+		}
+	}
+}
+
+func isRetryableError(err error) bool {
+	return errors.Is(err, ErrSerializationFailure) || errors.Is(err, ErrDeadlock) || errors.Is(err, ErrLockTimeout)
+}
+
 // Begin begins a transaction
 func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 	var (
@@ -402,6 +857,8 @@ func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 
 	if err != nil {
 		tx.AddError(err)
+	} else if _, ok := tx.Statement.ConnPool.(TxCommitter); ok {
+		tx.Statement.ConnPool = &txConnPool{ConnPool: tx.Statement.ConnPool}
 	}
 
 	return tx
@@ -429,7 +886,11 @@ func (db *DB) Rollback() *DB {
 
 func (db *DB) SavePoint(name string) *DB {
 	if savePointer, ok := db.Dialector.(SavePointerDialectorInterface); ok {
-		savePointer.SavePoint(db, name)
+		if err := savePointer.SavePoint(db, name); err != nil {
+			db.AddError(err)
+		} else if pool, ok := db.Statement.ConnPool.(*txConnPool); ok {
+			pool.savePoint(name)
+		}
 	} else {
 		db.AddError(ErrUnsupportedDriver)
 	}
@@ -438,7 +899,11 @@ func (db *DB) SavePoint(name string) *DB {
 
 func (db *DB) RollbackTo(name string) *DB {
 	if savePointer, ok := db.Dialector.(SavePointerDialectorInterface); ok {
-		savePointer.RollbackTo(db, name)
+		if err := savePointer.RollbackTo(db, name); err != nil {
+			db.AddError(err)
+		} else if pool, ok := db.Statement.ConnPool.(*txConnPool); ok {
+			pool.rollbackTo(name)
+		}
 	} else {
 		db.AddError(ErrUnsupportedDriver)
 	}
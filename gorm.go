@@ -28,10 +28,28 @@ type Config struct {
 	DryRun bool
 	// PrepareStmt executes the given query in cached statement
 	PrepareStmt bool
+	// PrepareStmtMaxSize max cached statements when PrepareStmt enabled, least recently used statements will be closed, unlimited if zero
+	PrepareStmtMaxSize int
 	// DisableAutomaticPing
 	DisableAutomaticPing bool
 	// DisableForeignKeyConstraintWhenMigrating
 	DisableForeignKeyConstraintWhenMigrating bool
+	// AllowDestructiveMigration allow AutoMigrate to narrow changed columns
+	AllowDestructiveMigration bool
+	// StrictIdentifier only allow columns and associations of the model in Select, Order, Group, Joins string arguments, use clause.Expr for raw SQL
+	StrictIdentifier bool
+	// DisableNestedTransaction join the outer transaction instead of using savepoints for nested transactions, for drivers without savepoint support
+	DisableNestedTransaction bool
+	// CreateBatchSize create slices in batches of the size
+	CreateBatchSize int
+	// UpdateBatchSize update records in batches of the size with UpdateBatch
+	UpdateBatchSize int
+	// RefreshDefaultValues re-select fields with database default values by primary keys after create, for dialects without RETURNING
+	RefreshDefaultValues bool
+	// DefaultQueryTimeout timeout of query and raw statements, no timeout if zero, Row and Rows are not limited, use WithContext for them
+	DefaultQueryTimeout time.Duration
+	// DefaultWriteTimeout timeout of create, update and delete statements, no timeout if zero
+	DefaultWriteTimeout time.Duration
 
 	// ClauseBuilders clause builder
 	ClauseBuilders map[string]clause.ClauseBuilder
@@ -64,6 +82,10 @@ type Session struct {
 	Context        context.Context
 	Logger         logger.Interface
 	NowFunc        func() time.Time		//用于拷贝给 db.Config
+	QueryTimeout   time.Duration
+	WriteTimeout   time.Duration
+
+	RefreshDefaultValues bool
 }
 
 // Open initialize db session based on dialector
@@ -112,6 +134,7 @@ func Open(dialector Dialector, config *Config) (db *DB, err error) {
 		db.ConnPool = &PreparedStmtDB{
 			ConnPool: db.ConnPool,
 			Stmts:    map[string]*sql.Stmt{},
+			MaxSize:  config.PrepareStmtMaxSize,
 		}
 	}
 
@@ -146,9 +169,13 @@ func (db *DB) Session(config *Session) *DB {
 		}
 	)
 
-	if config.Context != nil {
+	if config.Context != nil || config.PrepareStmt {
+		// clone statement, so the current one's context and conn pool won't be changed
 		tx.Statement = tx.Statement.clone()
 		tx.Statement.DB = tx
+	}
+
+	if config.Context != nil {
 		tx.Statement.Context = config.Context
 	}
 
@@ -156,6 +183,7 @@ func (db *DB) Session(config *Session) *DB {
 		tx.Statement.ConnPool = &PreparedStmtDB{
 			ConnPool: db.Config.ConnPool,
 			Stmts:    map[string]*sql.Stmt{},
+			MaxSize:  db.Config.PrepareStmtMaxSize,
 		}
 	}
 
@@ -175,6 +203,18 @@ func (db *DB) Session(config *Session) *DB {
 		tx.Config.NowFunc = config.NowFunc
 	}
 
+	if config.QueryTimeout != 0 {
+		tx.Config.DefaultQueryTimeout = config.QueryTimeout
+	}
+
+	if config.WriteTimeout != 0 {
+		tx.Config.DefaultWriteTimeout = config.WriteTimeout
+	}
+
+	if config.RefreshDefaultValues {
+		tx.Config.RefreshDefaultValues = true
+	}
+
 	return tx
 }
 
@@ -224,6 +264,13 @@ func (db *DB) Callback() *callbacks {
 // AddError add error to db
 // 加错误, 返回最新的错误
 func (db *DB) AddError(err error) error {
+	var translatedErr *TranslatedError
+	if translator, ok := db.Dialector.(ErrorTranslator); ok && err != nil && !errors.As(err, &translatedErr) {
+		if translated := translator.Translate(err); translated != err {
+			err = &TranslatedError{Err: translated, DriverErr: err}
+		}
+	}
+
 	if db.Error == nil {
 		db.Error = err
 	} else if err != nil {
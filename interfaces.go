@@ -20,6 +20,16 @@ type Dialector interface {
 	Explain(sql string, vars ...interface{}) string
 }
 
+// ErrorTranslator dialector that translates driver errors to portable errors, e.g: ErrDuplicatedKey, returns the error as it is if unknown
+type ErrorTranslator interface {
+	Translate(err error) error
+}
+
+// PlaceholderLimiter dialector that limits bind vars of a statement, CreateInBatches caps batch size with it
+type PlaceholderLimiter interface {
+	MaxPlaceholders() int
+}
+
 // Plugin GORM plugin interface
 type Plugin interface {
 	Name() string
@@ -53,6 +63,11 @@ type TxCommitter interface {
 	Rollback() error
 }
 
+// ConnPoolUnwrapper conn pool wrapping another one, e.g: the transaction started by Begin wraps the driver's *sql.Tx
+type ConnPoolUnwrapper interface {
+	Unwrap() ConnPool
+}
+
 type BeforeCreateInterface interface {
 	BeforeCreate(*DB) error
 }
@@ -88,3 +103,11 @@ type AfterDeleteInterface interface {
 type AfterFindInterface interface {
 	AfterFind(*DB) error
 }
+
+type AfterCommitInterface interface {
+	AfterCommit(*DB) error
+}
+
+type AfterRollbackInterface interface {
+	AfterRollback(*DB) error
+}
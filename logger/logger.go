@@ -43,6 +43,7 @@ type Config struct {
 	SlowThreshold time.Duration
 	Colorful      bool		//是否带颜色
 	LogLevel      LogLevel	//日志级别
+	Redactor      Redactor // redact vars of trace events
 }
 
 // Interface logger interface
@@ -54,6 +55,68 @@ type Interface interface {
 	Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error)
 }
 
+// TraceEvent structured trace event of a SQL execution
+type TraceEvent struct {
+	// Operation create, query, update, delete, row, raw
+	Operation string
+	Table     string
+	// SQL SQL with bind vars
+	SQL  string
+	Vars []interface{}
+	// VarColumns columns of Vars, column is blank if it is unknown or vars are not labeled, see TraceEventFilter
+	VarColumns []string
+	Rows       int64
+	Begin      time.Time
+	Duration   time.Duration
+	Error      error
+	// Caller file and line that executes the SQL
+	Caller string
+	// Explainer interpolate vars into SQL
+	Explainer func(sql string, vars ...interface{}) string
+}
+
+// Explain returns SQL with vars interpolated
+func (event TraceEvent) Explain() string {
+	if event.Explainer == nil {
+		return event.SQL
+	}
+	return event.Explainer(event.SQL, event.Vars...)
+}
+
+// RedactedValue replaces vars whose columns are unknown when redacting
+const RedactedValue = "[REDACTED]"
+
+// Redact replace vars with redactor's results, vars whose columns are unknown are replaced with RedactedValue,
+// event's vars are copied before redacting
+func (event *TraceEvent) Redact(redactor Redactor) {
+	vars := make([]interface{}, len(event.Vars))
+	for idx, v := range event.Vars {
+		if idx < len(event.VarColumns) && event.VarColumns[idx] != "" {
+			vars[idx] = redactor(event.VarColumns[idx], v)
+		} else {
+			vars[idx] = RedactedValue
+		}
+	}
+	event.Vars = vars
+}
+
+// Redactor returns the value to be logged for var bound to column, e.g: hide passwords
+type Redactor func(column string, value interface{}) interface{}
+
+// EventTracer logger receives structured trace events, it will be used instead of Interface.Trace if implemented
+type EventTracer interface {
+	TraceEvent(context.Context, TraceEvent)
+}
+
+// TraceEventFilter optional interface of EventTracer to avoid unnecessary work of every statement,
+// tracers don't implement it receive all events with labeled vars
+type TraceEventFilter interface {
+	// LabelVars returns true if vars should be labeled with columns when building statements, e.g: to redact them
+	LabelVars() bool
+	// TraceEventEnabled returns true if the event of the SQL execution will be traced
+	TraceEventEnabled(elapsed time.Duration, err error) bool
+}
+
 //默认打印变量
 var Default = New(log.New(os.Stdout, "\r\n", log.LstdFlags), Config{
 	SlowThreshold: 100 * time.Millisecond,
@@ -132,17 +195,43 @@ func (l logger) Error(ctx context.Context, msg string, data ...interface{}) {
 // Trace print sql message
 func (l logger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
 	if l.LogLevel > 0 {
-		elapsed := time.Now().Sub(begin)
-		switch {
-		case err != nil && l.LogLevel >= Error:
-			sql, rows := fc()
-			l.Printf(l.traceErrStr, utils.FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
-		case elapsed > l.SlowThreshold && l.SlowThreshold != 0 && l.LogLevel >= Warn:
-			sql, rows := fc()
-			l.Printf(l.traceWarnStr, utils.FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, rows, sql)
-		case l.LogLevel >= Info:
-			sql, rows := fc()
-			l.Printf(l.traceStr, utils.FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, rows, sql)
+		l.trace(utils.FileWithLineNum(), time.Now().Sub(begin), fc, err)
+	}
+}
+
+// LabelVars vars are only labeled if they will be redacted
+func (l logger) LabelVars() bool {
+	return l.Redactor != nil && l.LogLevel > 0
+}
+
+// TraceEventEnabled returns true if the SQL execution will be printed
+func (l logger) TraceEventEnabled(elapsed time.Duration, err error) bool {
+	return err != nil && l.LogLevel >= Error || elapsed > l.SlowThreshold && l.SlowThreshold != 0 && l.LogLevel >= Warn || l.LogLevel >= Info
+}
+
+// TraceEvent print sql message of trace event, vars are redacted with config's Redactor
+func (l logger) TraceEvent(ctx context.Context, event TraceEvent) {
+	if l.LogLevel > 0 {
+		if l.Redactor != nil {
+			event.Redact(l.Redactor)
 		}
+
+		l.trace(event.Caller, event.Duration, func() (string, int64) {
+			return event.Explain(), event.Rows
+		}, event.Error)
+	}
+}
+
+func (l logger) trace(caller string, elapsed time.Duration, fc func() (string, int64), err error) {
+	switch {
+	case err != nil && l.LogLevel >= Error:
+		sql, rows := fc()
+		l.Printf(l.traceErrStr, caller, err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+	case elapsed > l.SlowThreshold && l.SlowThreshold != 0 && l.LogLevel >= Warn:
+		sql, rows := fc()
+		l.Printf(l.traceWarnStr, caller, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+	case l.LogLevel >= Info:
+		sql, rows := fc()
+		l.Printf(l.traceStr, caller, float64(elapsed.Nanoseconds())/1e6, rows, sql)
 	}
 }
@@ -0,0 +1,137 @@
+package gorm
+
+import (
+	"gorm.io/gorm/clause"
+)
+
+// MergeClauseBuilders clause builders that render INSERT with ON CONFLICT as a MERGE statement, dialects like SQL Server could register them with Config.ClauseBuilders
+func MergeClauseBuilders() map[string]clause.ClauseBuilder {
+	return map[string]clause.ClauseBuilder{
+		"INSERT":      buildMergeInsert,
+		"VALUES":      buildMergeValues,
+		"ON CONFLICT": buildMergeOnConflict,
+	}
+}
+
+// mergeClauses returns the values and on conflict clauses of the statement if it should be built as MERGE
+func mergeClauses(builder clause.Builder) (stmt *Statement, values clause.Values, onConflict clause.OnConflict, ok bool) {
+	if stmt, ok = builder.(*Statement); ok {
+		if c, exists := stmt.Clauses["ON CONFLICT"]; exists {
+			if onConflict, ok = c.Expression.(clause.OnConflict); ok {
+				if c, exists := stmt.Clauses["VALUES"]; exists {
+					values, ok = c.Expression.(clause.Values)
+					return stmt, values, onConflict, ok && len(values.Columns) > 0
+				}
+			}
+		}
+	}
+	return nil, values, onConflict, false
+}
+
+func mergeTable(stmt *Statement) clause.Table {
+	if c, ok := stmt.Clauses["INSERT"]; ok {
+		if insert, ok := c.Expression.(clause.Insert); ok && insert.Table.Name != "" {
+			return insert.Table
+		}
+	}
+	return clause.Table{Name: clause.CurrentTable}
+}
+
+func buildMergeInsert(c clause.Clause, builder clause.Builder) {
+	stmt, _, _, ok := mergeClauses(builder)
+	if !ok {
+		c.Build(builder)
+		return
+	}
+
+	stmt.WriteString("MERGE INTO ")
+	stmt.WriteQuoted(mergeTable(stmt))
+}
+
+func buildMergeValues(c clause.Clause, builder clause.Builder) {
+	stmt, values, _, ok := mergeClauses(builder)
+	if !ok {
+		c.Build(builder)
+		return
+	}
+
+	stmt.WriteString("USING (VALUES ")
+	for idx, value := range values.Values {
+		if idx > 0 {
+			stmt.WriteByte(',')
+		}
+
+		stmt.WriteByte('(')
+		stmt.AddVar(stmt, value...)
+		stmt.WriteByte(')')
+	}
+
+	stmt.WriteString(") AS ")
+	stmt.WriteQuoted("excluded")
+	stmt.WriteString(" (")
+	for idx, column := range values.Columns {
+		if idx > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.WriteQuoted(column)
+	}
+	stmt.WriteByte(')')
+}
+
+func buildMergeOnConflict(c clause.Clause, builder clause.Builder) {
+	stmt, values, onConflict, ok := mergeClauses(builder)
+	if !ok {
+		c.Build(builder)
+		return
+	}
+
+	columns := onConflict.Columns
+	if len(columns) == 0 && stmt.Schema != nil {
+		for _, field := range stmt.Schema.PrimaryFields {
+			columns = append(columns, clause.Column{Name: field.DBName})
+		}
+	}
+
+	table := mergeTable(stmt)
+	if table.Alias != "" {
+		table = clause.Table{Name: table.Alias}
+	}
+
+	stmt.WriteString("ON ")
+	for idx, column := range columns {
+		if idx > 0 {
+			stmt.WriteString(" AND ")
+		}
+		stmt.WriteQuoted(clause.Column{Table: table.Name, Name: column.Name})
+		stmt.WriteString(" = ")
+		stmt.WriteQuoted(clause.Excluded(column.Name))
+	}
+
+	if !onConflict.DoNothing && len(onConflict.DoUpdates) > 0 {
+		stmt.WriteString(" WHEN MATCHED ")
+		if len(onConflict.Where.Exprs) > 0 {
+			stmt.WriteString("AND ")
+			onConflict.Where.Build(stmt)
+			stmt.WriteByte(' ')
+		}
+		stmt.WriteString("THEN UPDATE SET ")
+		onConflict.DoUpdates.Build(stmt)
+	}
+
+	stmt.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+	for idx, column := range values.Columns {
+		if idx > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.WriteQuoted(column)
+	}
+
+	stmt.WriteString(") VALUES (")
+	for idx, column := range values.Columns {
+		if idx > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.WriteQuoted(clause.Excluded(column.Name))
+	}
+	stmt.WriteString(");")
+}
@@ -0,0 +1,139 @@
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lockRetryInterval interval to retry acquiring the migration lock held by other instances
+var lockRetryInterval = 100 * time.Millisecond
+
+// Locker migration lock, prevents multiple instances running migrations at the same time
+type Locker interface {
+	Lock(*gorm.DB) error
+	Unlock(*gorm.DB) error
+}
+
+func newLocker(db *gorm.DB, options Options) Locker {
+	switch db.Dialector.Name() {
+	case "postgres":
+		hash := fnv.New64a()
+		hash.Write([]byte(options.TableName))
+		return postgresLocker{key: int64(hash.Sum64()), timeout: options.LockTimeout}
+	case "mysql":
+		return mysqlLocker{name: options.TableName, timeout: options.LockTimeout}
+	default:
+		return tableLocker{table: options.TableName + "_lock", name: options.TableName, timeout: options.LockTimeout, expiration: options.LockExpiration}
+	}
+}
+
+// acquire calls try until the lock acquired, returns ErrAcquireLockFailed if not acquired before timeout
+func acquire(timeout time.Duration, try func() (bool, error)) error {
+	for deadline := time.Now().Add(timeout); ; time.Sleep(lockRetryInterval) {
+		if locked, err := try(); err != nil || locked {
+			return err
+		} else if time.Now().After(deadline) {
+			return ErrAcquireLockFailed
+		}
+	}
+}
+
+// postgresLocker session level advisory lock, requires lock and unlock in the same connection
+type postgresLocker struct {
+	key     int64
+	timeout time.Duration
+}
+
+func (l postgresLocker) Lock(db *gorm.DB) error {
+	return acquire(l.timeout, func() (locked bool, err error) {
+		err = db.Raw("SELECT pg_try_advisory_lock(?)", l.key).Row().Scan(&locked)
+		return
+	})
+}
+
+func (l postgresLocker) Unlock(db *gorm.DB) error {
+	return db.Exec("SELECT pg_advisory_unlock(?)", l.key).Error
+}
+
+// mysqlLocker named lock, requires lock and unlock in the same connection
+type mysqlLocker struct {
+	name    string
+	timeout time.Duration
+}
+
+func (l mysqlLocker) Lock(db *gorm.DB) error {
+	// GET_LOCK returns 1 if acquired, 0 if timed out, NULL if an error occurred, e.g: killed
+	var locked sql.NullInt64
+	if err := db.Raw("SELECT GET_LOCK(?, ?)", l.name, int(l.timeout.Seconds())).Row().Scan(&locked); err != nil {
+		return err
+	} else if !locked.Valid || locked.Int64 != 1 {
+		return ErrAcquireLockFailed
+	}
+	return nil
+}
+
+func (l mysqlLocker) Unlock(db *gorm.DB) error {
+	return db.Exec("SELECT RELEASE_LOCK(?)", l.name).Error
+}
+
+// tableLocker lock by inserting a row into lock table, used for dialects don't support advisory lock,
+// locks held longer than expiration are considered left by crashed instances and released
+type tableLocker struct {
+	table      string
+	name       string
+	timeout    time.Duration
+	expiration time.Duration
+}
+
+type lockRecord struct {
+	ID       string `gorm:"primarykey;size:255"`
+	LockedAt time.Time
+}
+
+func (l tableLocker) Lock(db *gorm.DB) error {
+	if err := db.Table(l.table).AutoMigrate(&lockRecord{}); err != nil {
+		return err
+	}
+
+	return acquire(l.timeout, func() (bool, error) {
+		err := db.Table(l.table).Create(&lockRecord{ID: l.name, LockedAt: db.NowFunc()}).Error
+		if err == nil {
+			return true, nil
+		} else if !isUniqueViolation(err) {
+			return false, err
+		}
+
+		if l.expiration > 0 {
+			// release stale lock, it will be acquired in next try
+			err = db.Table(l.table).Where("id = ? AND locked_at < ?", l.name, db.NowFunc().Add(-l.expiration)).Delete(&lockRecord{}).Error
+		}
+		return false, err
+	})
+}
+
+func (l tableLocker) Unlock(db *gorm.DB) error {
+	return db.Table(l.table).Where("id = ?", l.name).Delete(&lockRecord{}).Error
+}
+
+// isUniqueViolation returns true if the error is caused by inserting a lock record that already exists
+func isUniqueViolation(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, keyword := range []string{
+		"unique constraint", // SQLite, PostgreSQL
+		"duplicate",         // MySQL, SQL Server
+	} {
+		if strings.Contains(msg, keyword) {
+			return true
+		}
+	}
+	return false
+}
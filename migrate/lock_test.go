@@ -0,0 +1,96 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type namedDialector struct {
+	tests.DummyDialector
+	name string
+}
+
+func (d namedDialector) Name() string {
+	return d.name
+}
+
+func TestNewLocker(t *testing.T) {
+	options := Options{TableName: "migrations", LockTimeout: time.Second, LockExpiration: time.Hour}
+
+	for name, expected := range map[string]Locker{
+		"postgres": postgresLocker{},
+		"mysql":    mysqlLocker{name: "migrations", timeout: time.Second},
+		"sqlite":   tableLocker{table: "migrations_lock", name: "migrations", timeout: time.Second, expiration: time.Hour},
+	} {
+		db, _ := gorm.Open(namedDialector{name: name}, nil)
+		locker := newLocker(db, options)
+		if fmt.Sprintf("%T", locker) != fmt.Sprintf("%T", expected) {
+			t.Errorf("%v should use %T, but got %T", name, expected, locker)
+		} else if pl, ok := locker.(postgresLocker); ok {
+			if pl.key == 0 || pl.timeout != time.Second {
+				t.Errorf("postgres locker should be keyed by table name with timeout, got %+v", pl)
+			}
+		} else if locker != expected {
+			t.Errorf("%v locker should be %+v, but got %+v", name, expected, locker)
+		}
+	}
+}
+
+func TestNewDefaultOptions(t *testing.T) {
+	m := New(nil, nil, nil)
+	if m.options.TableName != "migrations" || m.options.LockTimeout != time.Minute || m.options.LockExpiration != time.Hour {
+		t.Errorf("default options should be used, got %+v", m.options)
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	defer func(interval time.Duration) { lockRetryInterval = interval }(lockRetryInterval)
+	lockRetryInterval = time.Millisecond
+
+	var tries int
+	if err := acquire(time.Second, func() (bool, error) {
+		tries++
+		return tries == 3, nil
+	}); err != nil || tries != 3 {
+		t.Errorf("should retry until lock acquired, got %v tries, error %v", tries, err)
+	}
+
+	tries = 0
+	if err := acquire(10*time.Millisecond, func() (bool, error) {
+		tries++
+		return false, nil
+	}); !errors.Is(err, ErrAcquireLockFailed) || tries < 2 {
+		t.Errorf("should returns ErrAcquireLockFailed after retrying, got %v tries, error %v", tries, err)
+	}
+
+	tries = 0
+	failed := errors.New("failed")
+	if err := acquire(time.Second, func() (bool, error) {
+		tries++
+		return false, failed
+	}); !errors.Is(err, failed) || tries != 1 {
+		t.Errorf("should returns error without retrying, got %v tries, error %v", tries, err)
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	for err, expected := range map[error]bool{
+		gorm.ErrDuplicatedKey: true,
+		errors.New("UNIQUE constraint failed: migrations_lock.id"):                                 true,
+		errors.New(`ERROR: duplicate key value violates unique constraint "migrations_lock_pkey"`): true,
+		errors.New("Error 1062: Duplicate entry 'migrations' for key 'PRIMARY'"):                   true,
+		errors.New("mssql: Violation of PRIMARY KEY constraint. Cannot insert duplicate key"):      true,
+		errors.New("database is locked"):                                                           false,
+		errors.New("no such table: migrations_lock"):                                               false,
+		fmt.Errorf("wrapped: %w", gorm.ErrDuplicatedKey):                                           true,
+	} {
+		if isUniqueViolation(err) != expected {
+			t.Errorf("unique violation of %v should be %v", err, expected)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrMissingID migration's id is blank
+	ErrMissingID = errors.New("missing migration id")
+	// ErrDuplicatedID migration's id has been registered
+	ErrDuplicatedID = errors.New("duplicated migration id")
+	// ErrUnknownMigration migration is not registered
+	ErrUnknownMigration = errors.New("unknown migration")
+	// ErrNoAppliedMigration no migration has been applied
+	ErrNoAppliedMigration = errors.New("no applied migration")
+	// ErrRollbackNotDefined migration doesn't have rollback func
+	ErrRollbackNotDefined = errors.New("rollback not defined")
+	// ErrAcquireLockFailed failed to acquire migration lock, e.g: not acquired in time
+	ErrAcquireLockFailed = errors.New("failed to acquire migration lock")
+)
+
+// transactionalDDL dialects that support running DDL in transaction
+var transactionalDDL = map[string]bool{"postgres": true, "sqlite": true, "sqlserver": true}
+
+// Migration versioned migration, migrations are ordered by ID
+type Migration struct {
+	ID       string
+	Migrate  func(*gorm.DB) error
+	Rollback func(*gorm.DB) error
+}
+
+// Options migrator options
+type Options struct {
+	// TableName table to track applied migrations, default is `migrations`
+	TableName string
+	// DisableTransaction don't run migration in transaction even the dialect supports transactional DDL
+	DisableTransaction bool
+	// LockTimeout how long to wait for other instances' migration lock, default is 1 minute
+	LockTimeout time.Duration
+	// LockExpiration locks held longer than it are released as stale ones left by crashed instances, default is 1 hour,
+	// only used by dialects without advisory lock, whose locks are released with the connection
+	LockExpiration time.Duration
+}
+
+// Migrator versioned migrations runner
+type Migrator struct {
+	db         *gorm.DB
+	options    Options
+	migrations []*Migration
+}
+
+// migrationRecord applied migration
+type migrationRecord struct {
+	ID        string `gorm:"primarykey;size:255"`
+	AppliedAt time.Time
+}
+
+// New create migrator with migrations, options could be nil
+func New(db *gorm.DB, options *Options, migrations []*Migration) *Migrator {
+	m := &Migrator{db: db, migrations: make([]*Migration, len(migrations))}
+	if options != nil {
+		m.options = *options
+	}
+
+	if m.options.TableName == "" {
+		m.options.TableName = "migrations"
+	}
+
+	if m.options.LockTimeout == 0 {
+		m.options.LockTimeout = time.Minute
+	}
+
+	if m.options.LockExpiration == 0 {
+		m.options.LockExpiration = time.Hour
+	}
+
+	copy(m.migrations, migrations)
+	sort.SliceStable(m.migrations, func(i, j int) bool {
+		return m.migrations[i].ID < m.migrations[j].ID
+	})
+	return m
+}
+
+// Migrate run all pending migrations
+func (m *Migrator) Migrate() error {
+	return m.migrate("")
+}
+
+// MigrateTo run pending migrations until the migration with id (included)
+func (m *Migrator) MigrateTo(id string) error {
+	if m.find(id) == nil {
+		return fmt.Errorf("%w: %v", ErrUnknownMigration, id)
+	}
+	return m.migrate(id)
+}
+
+// RollbackLast rollback the last applied migration
+func (m *Migrator) RollbackLast() error {
+	return m.run(func(db *gorm.DB) error {
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		} else if len(applied) == 0 {
+			return ErrNoAppliedMigration
+		}
+
+		migration := m.find(applied[len(applied)-1])
+		if migration == nil {
+			return fmt.Errorf("%w: %v", ErrUnknownMigration, applied[len(applied)-1])
+		}
+		return m.rollback(db, migration)
+	})
+}
+
+// RollbackTo rollback applied migrations after the migration with id (excluded), in reverse order
+func (m *Migrator) RollbackTo(id string) error {
+	if m.find(id) == nil {
+		return fmt.Errorf("%w: %v", ErrUnknownMigration, id)
+	}
+
+	return m.run(func(db *gorm.DB) error {
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		}
+
+		for i := len(applied) - 1; i >= 0 && applied[i] > id; i-- {
+			migration := m.find(applied[i])
+			if migration == nil {
+				return fmt.Errorf("%w: %v", ErrUnknownMigration, applied[i])
+			}
+
+			if err := m.rollback(db, migration); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Applied returns applied migrations' ids in order
+func (m *Migrator) Applied() (ids []string, err error) {
+	err = m.run(func(db *gorm.DB) error {
+		ids, err = m.applied(db)
+		return err
+	})
+	return
+}
+
+func (m *Migrator) migrate(id string) error {
+	for idx, migration := range m.migrations {
+		if migration.ID == "" {
+			return ErrMissingID
+		} else if idx > 0 && migration.ID == m.migrations[idx-1].ID {
+			return fmt.Errorf("%w: %v", ErrDuplicatedID, migration.ID)
+		}
+	}
+
+	return m.run(func(db *gorm.DB) error {
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		}
+
+		appliedMap := map[string]bool{}
+		for _, id := range applied {
+			appliedMap[id] = true
+		}
+
+		for _, migration := range m.migrations {
+			if !appliedMap[migration.ID] {
+				if err := m.transaction(db, func(tx *gorm.DB) error {
+					if err := migration.Migrate(tx); err != nil {
+						return err
+					}
+					return tx.Table(m.options.TableName).Create(&migrationRecord{ID: migration.ID, AppliedAt: tx.NowFunc()}).Error
+				}); err != nil {
+					return fmt.Errorf("failed to run migration %v: %w", migration.ID, err)
+				}
+			}
+
+			if migration.ID == id {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) rollback(db *gorm.DB, migration *Migration) error {
+	if migration.Rollback == nil {
+		return fmt.Errorf("%w: %v", ErrRollbackNotDefined, migration.ID)
+	}
+
+	if err := m.transaction(db, func(tx *gorm.DB) error {
+		if err := migration.Rollback(tx); err != nil {
+			return err
+		}
+		return tx.Table(m.options.TableName).Where("id = ?", migration.ID).Delete(&migrationRecord{}).Error
+	}); err != nil {
+		return fmt.Errorf("failed to rollback migration %v: %w", migration.ID, err)
+	}
+	return nil
+}
+
+func (m *Migrator) find(id string) *Migration {
+	for _, migration := range m.migrations {
+		if migration.ID == id {
+			return migration
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) applied(db *gorm.DB) (ids []string, err error) {
+	err = db.Table(m.options.TableName).Model(&migrationRecord{}).Order("id").Pluck("id", &ids).Error
+	return
+}
+
+// transaction run fc in transaction if the dialect supports transactional DDL
+func (m *Migrator) transaction(db *gorm.DB, fc func(tx *gorm.DB) error) error {
+	if !m.options.DisableTransaction && transactionalDDL[db.Dialector.Name()] {
+		return db.Transaction(fc)
+	}
+	return fc(db)
+}
+
+// run fc with a pinned connection if possible, migration lock is held and migrations table is created during running
+func (m *Migrator) run(fc func(*gorm.DB) error) error {
+	db := m.db.Session(&gorm.Session{})
+	if _, err := db.DB(); err != nil {
+		// customized conn pools can't be pinned
+		return m.locked(db, fc)
+	}
+
+	// lock and unlock must use the same connection, pin it even if the conn pool prepares statements
+	return db.Connection(func(tx *gorm.DB) error {
+		return m.locked(tx, fc)
+	})
+}
+
+// locked run fc with migration lock held
+func (m *Migrator) locked(db *gorm.DB, fc func(*gorm.DB) error) (err error) {
+	locker := newLocker(db, m.options)
+	if err := locker.Lock(db); err != nil {
+		return err
+	}
+
+	defer func() {
+		if unlockErr := locker.Unlock(db); err == nil {
+			err = unlockErr
+		}
+	}()
+
+	if err = db.Table(m.options.TableName).AutoMigrate(&migrationRecord{}); err == nil {
+		err = fc(db)
+	}
+	return
+}
@@ -24,9 +24,45 @@ type ViewOption struct {
 	Query       *DB
 }
 
+// SchemaChangeType planned schema change type
+type SchemaChangeType string
+
+const (
+	CreateTableChange      SchemaChangeType = "create_table"
+	AddColumnChange        SchemaChangeType = "add_column"
+	AlterColumnChange      SchemaChangeType = "alter_column"
+	CreateIndexChange      SchemaChangeType = "create_index"
+	CreateConstraintChange SchemaChangeType = "create_constraint"
+)
+
+// SchemaChange planned schema change, Name is the column, index or constraint's name
+type SchemaChange struct {
+	Type    SchemaChangeType
+	Table   string
+	Name    string
+	Details []string
+	SQL     []string
+	// Destructive the change narrows column and might lose data
+	Destructive bool
+}
+
+// MigrationPlan schema changes that AutoMigrate will apply
+type MigrationPlan struct {
+	Changes []SchemaChange
+}
+
+// SQL returns pending SQL of all changes
+func (plan *MigrationPlan) SQL() (sqls []string) {
+	for _, change := range plan.Changes {
+		sqls = append(sqls, change.SQL...)
+	}
+	return
+}
+
 type Migrator interface {
 	// AutoMigrate
 	AutoMigrate(dst ...interface{}) error
+	Plan(dst ...interface{}) (*MigrationPlan, error)
 
 	// Database
 	CurrentDatabase() string
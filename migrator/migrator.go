@@ -79,7 +79,6 @@ func (m Migrator) FullDataTypeOf(field *schema.Field) (expr clause.Expr) {
 
 // AutoMigrate
 func (m Migrator) AutoMigrate(values ...interface{}) error {
-	// TODO smart migrate data type
 	for _, value := range m.ReorderModels(values, true) {
 		tx := m.DB.Session(&gorm.Session{})
 		if !tx.Migrator().HasTable(value) {
@@ -88,6 +87,36 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 			}
 		} else {
 			if err := m.RunWithValue(value, func(stmt *gorm.Statement) (errr error) {
+				columnTypes, err := tx.Migrator().ColumnTypes(value)
+				if err != nil {
+					return err
+				}
+
+				columnTypesMap := map[string]*sql.ColumnType{}
+				for _, columnType := range columnTypes {
+					columnTypesMap[strings.ToLower(columnType.Name())] = columnType
+				}
+
+				var (
+					attrs        = m.columnAttributes(stmt)
+					alterColumns []string
+				)
+
+				for _, dbName := range stmt.Schema.DBNames {
+					if columnType, ok := columnTypesMap[strings.ToLower(dbName)]; ok {
+						changes := m.columnChanges(stmt.Schema.FieldsByDBName[dbName], columnType, attrs)
+						for _, change := range changes {
+							if change.Destructive && !m.DB.AllowDestructiveMigration {
+								return fmt.Errorf("%w: %v.%v %v", gorm.ErrDestructiveMigration, stmt.Table, dbName, change)
+							}
+						}
+
+						if len(changes) > 0 {
+							alterColumns = append(alterColumns, dbName)
+						}
+					}
+				}
+
 				for _, field := range stmt.Schema.FieldsByDBName {
 					if !tx.Migrator().HasColumn(value, field.DBName) {
 						if err := tx.Migrator().AddColumn(value, field.DBName); err != nil {
@@ -96,6 +125,12 @@ func (m Migrator) AutoMigrate(values ...interface{}) error {
 					}
 				}
 
+				for _, dbName := range alterColumns {
+					if err := tx.Migrator().AlterColumn(value, dbName); err != nil {
+						return err
+					}
+				}
+
 				for _, rel := range stmt.Schema.Relationships.Relations {
 					if !m.DB.Config.DisableForeignKeyConstraintWhenMigrating {
 						if constraint := rel.ParseConstraint(); constraint != nil {
@@ -329,6 +364,7 @@ func (m Migrator) ColumnTypes(value interface{}) (columnTypes []*sql.ColumnType,
 	err = m.RunWithValue(value, func(stmt *gorm.Statement) error {
 		rows, err := m.DB.Raw("select * from ?", clause.Table{Name: stmt.Table}).Rows()
 		if err == nil {
+			defer rows.Close()
 			columnTypes, err = rows.ColumnTypes()
 		}
 		return err
@@ -0,0 +1,400 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	dataTypeSizeRegexp = regexp.MustCompile(`\((\d+)(?:\s*,\s*(\d+))?\)`)
+	dataTypeModifiers  = []string{" primary key", " autoincrement", " auto_increment", " unsigned"}
+	dataTypeWidenings  = [][]string{
+		{"tinyint", "smallint", "mediumint", "integer", "bigint"},
+		{"real", "double precision"},
+		{"char", "varchar", "text"},
+	}
+	// dataTypeAliases equivalent data types, mysql driver reports text, blob columns' types without their sizes, e.g: longtext -> TEXT
+	dataTypeAliases = map[string]string{
+		"int": "integer", "int4": "integer", "serial": "integer", "int8": "bigint", "bigserial": "bigint", "int2": "smallint", "smallserial": "smallint",
+		"bool": "boolean", "float4": "real", "float8": "double precision", "double": "double precision",
+		"numeric": "decimal", "character varying": "varchar", "timestamptz": "timestamp with time zone",
+		"tinytext": "text", "mediumtext": "text", "longtext": "text", "tinyblob": "blob", "mediumblob": "blob", "longblob": "blob",
+	}
+	// booleanDataTypes data types of boolean columns, e.g: mysql creates boolean as tinyint(1), which is reported as tinyint
+	booleanDataTypes = map[string]bool{"boolean": true, "tinyint": true}
+)
+
+// Plan returns schema changes AutoMigrate will apply to models, changes' SQL are rendered without being executed
+func (m Migrator) Plan(values ...interface{}) (*gorm.MigrationPlan, error) {
+	plan := &gorm.MigrationPlan{}
+
+	for _, value := range m.ReorderModels(values, true) {
+		tx := m.DB.Session(&gorm.Session{})
+		if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			if !tx.Migrator().HasTable(value) {
+				return m.planChange(plan, gorm.SchemaChange{Type: gorm.CreateTableChange, Table: stmt.Table}, func(migrator gorm.Migrator) error {
+					return migrator.CreateTable(value)
+				})
+			}
+
+			columnTypes, err := tx.Migrator().ColumnTypes(value)
+			if err != nil {
+				return err
+			}
+
+			columnTypesMap := map[string]*sql.ColumnType{}
+			for _, columnType := range columnTypes {
+				columnTypesMap[strings.ToLower(columnType.Name())] = columnType
+			}
+			attrs := m.columnAttributes(stmt)
+
+			for _, dbName := range stmt.Schema.DBNames {
+				field := stmt.Schema.FieldsByDBName[dbName]
+				if columnType, ok := columnTypesMap[strings.ToLower(dbName)]; !ok {
+					if err := m.planChange(plan, gorm.SchemaChange{Type: gorm.AddColumnChange, Table: stmt.Table, Name: dbName}, func(migrator gorm.Migrator) error {
+						return migrator.AddColumn(value, dbName)
+					}); err != nil {
+						return err
+					}
+				} else if changes := m.columnChanges(field, columnType, attrs); len(changes) > 0 {
+					change := gorm.SchemaChange{Type: gorm.AlterColumnChange, Table: stmt.Table, Name: dbName}
+					for _, c := range changes {
+						change.Details = append(change.Details, c.String())
+						change.Destructive = change.Destructive || c.Destructive
+					}
+
+					if err := m.planChange(plan, change, func(migrator gorm.Migrator) error {
+						return migrator.AlterColumn(value, dbName)
+					}); err != nil {
+						return err
+					}
+				}
+			}
+
+			var constraints []string
+			if !m.DB.Config.DisableForeignKeyConstraintWhenMigrating {
+				for _, rel := range stmt.Schema.Relationships.Relations {
+					if constraint := rel.ParseConstraint(); constraint != nil && constraint.Schema == stmt.Schema {
+						constraints = append(constraints, constraint.Name)
+					}
+				}
+			}
+
+			for _, chk := range stmt.Schema.ParseCheckConstraints() {
+				constraints = append(constraints, chk.Name)
+			}
+
+			for _, name := range constraints {
+				if !tx.Migrator().HasConstraint(value, name) {
+					if err := m.planChange(plan, gorm.SchemaChange{Type: gorm.CreateConstraintChange, Table: stmt.Table, Name: name}, func(migrator gorm.Migrator) error {
+						return migrator.CreateConstraint(value, name)
+					}); err != nil {
+						return err
+					}
+				}
+			}
+
+			for _, idx := range stmt.Schema.ParseIndexes() {
+				if !tx.Migrator().HasIndex(value, idx.Name) {
+					if err := m.planChange(plan, gorm.SchemaChange{Type: gorm.CreateIndexChange, Table: stmt.Table, Name: idx.Name}, func(migrator gorm.Migrator) error {
+						return migrator.CreateIndex(value, idx.Name)
+					}); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// planChange render change's SQL with a migrator whose write operations are recorded rather than executed
+func (m Migrator) planChange(plan *gorm.MigrationPlan, change gorm.SchemaChange, fc func(gorm.Migrator) error) error {
+	recorder := &recordConnPool{ConnPool: m.DB.Statement.ConnPool, dialector: m.Dialector}
+	tx := m.DB.Session(&gorm.Session{Context: m.DB.Statement.Context})
+	tx.Statement.ConnPool = recorder
+
+	if err := fc(tx.Migrator()); err != nil {
+		return err
+	}
+
+	change.SQL = recorder.sqls
+	plan.Changes = append(plan.Changes, change)
+	return nil
+}
+
+// ColumnChange changed column attribute, e.g: `size: 100 -> 50`
+type ColumnChange struct {
+	Attribute string
+	From      string
+	To        string
+	// Destructive changing the attribute narrows column and might lose data
+	Destructive bool
+}
+
+func (change ColumnChange) String() string {
+	return fmt.Sprintf("%v: %v -> %v", change.Attribute, change.From, change.To)
+}
+
+// ColumnChanges compare field with column in database, returns changed attributes
+func (m Migrator) ColumnChanges(field *schema.Field, columnType *sql.ColumnType) (changes []ColumnChange) {
+	var (
+		fieldType = strings.ToLower(m.DataTypeOf(field))
+		dbType    = strings.ToLower(columnType.DatabaseTypeName())
+	)
+
+	fieldDataType, dbDataType := normalizeDataType(fieldType), normalizeDataType(dbType)
+	if field.DataType == schema.Bool && booleanDataTypes[fieldDataType] && booleanDataTypes[dbDataType] {
+		dbDataType = fieldDataType
+	}
+
+	if fieldDataType != dbDataType {
+		changes = append(changes, ColumnChange{Attribute: "type", From: dbType, To: fieldType, Destructive: !isWideningDataType(dbDataType, fieldDataType)})
+	}
+
+	fieldSizes := dataTypeSizeRegexp.FindStringSubmatch(fieldType)
+	columnSizes := dataTypeSizeRegexp.FindStringSubmatch(dbType)
+	if field.DataType == schema.String || field.DataType == schema.Bytes {
+		size := int64(field.Size)
+		if len(fieldSizes) > 1 {
+			size, _ = strconv.ParseInt(fieldSizes[1], 10, 64)
+		}
+
+		length, ok := columnType.Length()
+		if !ok && len(columnSizes) > 1 {
+			length, _ = strconv.ParseInt(columnSizes[1], 10, 64)
+			ok = true
+		}
+
+		if ok && size > 0 && length > 0 && length != size {
+			changes = append(changes, ColumnChange{Attribute: "size", From: strconv.FormatInt(length, 10), To: strconv.FormatInt(size, 10), Destructive: size < length})
+		}
+	}
+
+	if field.Precision > 0 {
+		precision, scale, ok := columnType.DecimalSize()
+		if !ok && len(columnSizes) > 2 && columnSizes[2] != "" {
+			precision, _ = strconv.ParseInt(columnSizes[1], 10, 64)
+			scale, _ = strconv.ParseInt(columnSizes[2], 10, 64)
+			ok = true
+		}
+
+		if ok && precision != int64(field.Precision) {
+			changes = append(changes, ColumnChange{Attribute: "precision", From: strconv.FormatInt(precision, 10), To: strconv.Itoa(field.Precision), Destructive: int64(field.Precision) < precision})
+		}
+
+		if fieldScale, err := strconv.ParseInt(field.TagSettings["SCALE"], 10, 64); ok && err == nil && scale != fieldScale {
+			changes = append(changes, ColumnChange{Attribute: "scale", From: strconv.FormatInt(scale, 10), To: strconv.FormatInt(fieldScale, 10), Destructive: fieldScale < scale})
+		}
+	}
+
+	if nullable, ok := m.columnNullable(columnType); ok && !field.PrimaryKey && nullable == field.NotNull {
+		changes = append(changes, nullableChange(field, nullable))
+	}
+
+	return
+}
+
+// columnNullable returns column's nullability reported by the driver, sqlite driver always reports columns as nullable
+func (m Migrator) columnNullable(columnType *sql.ColumnType) (nullable bool, ok bool) {
+	if m.Dialector.Name() == "sqlite" {
+		return false, false
+	}
+	return columnType.Nullable()
+}
+
+func nullableChange(field *schema.Field, nullable bool) ColumnChange {
+	return ColumnChange{Attribute: "nullable", From: strconv.FormatBool(nullable), To: strconv.FormatBool(!field.NotNull), Destructive: nullable}
+}
+
+// columnAttributes column's default value, comment and nullability, which are not provided by sql.ColumnType,
+// invalid attributes are not supported by the dialect and won't be compared
+type columnAttributes struct {
+	Default  sql.NullString
+	Comment  sql.NullString
+	Nullable sql.NullBool
+}
+
+// columnAttributes returns columns' attributes from INFORMATION_SCHEMA of mysql, postgres or table_info pragma of sqlite,
+// comments are only compared on mysql, postgres dialect doesn't create comments
+func (m Migrator) columnAttributes(stmt *gorm.Statement) map[string]columnAttributes {
+	var (
+		results = map[string]columnAttributes{}
+		rows    *sql.Rows
+		err     error
+	)
+
+	switch m.Dialector.Name() {
+	case "mysql":
+		rows, err = m.DB.Raw(
+			"SELECT column_name, column_default, column_comment FROM INFORMATION_SCHEMA.columns WHERE table_schema = ? AND table_name = ?",
+			m.DB.Migrator().CurrentDatabase(), stmt.Table,
+		).Rows()
+	case "postgres":
+		rows, err = m.DB.Raw(
+			"SELECT column_name, column_default, is_nullable = 'YES' FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name = ?",
+			stmt.Table,
+		).Rows()
+	case "sqlite":
+		rows, err = m.DB.Raw("PRAGMA table_info(" + stmt.Quote(stmt.Table) + ")").Rows()
+	default:
+		return results
+	}
+
+	if err != nil {
+		return results
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			name  string
+			attrs columnAttributes
+		)
+
+		switch m.Dialector.Name() {
+		case "mysql":
+			err = rows.Scan(&name, &attrs.Default, &attrs.Comment)
+		case "postgres":
+			err = rows.Scan(&name, &attrs.Default, &attrs.Nullable)
+		case "sqlite":
+			var (
+				cid, pk  int
+				dataType string
+				notNull  bool
+			)
+			err = rows.Scan(&cid, &name, &dataType, &notNull, &attrs.Default, &pk)
+			attrs.Nullable = sql.NullBool{Bool: !notNull, Valid: true}
+		}
+
+		if err == nil {
+			results[strings.ToLower(name)] = attrs
+		}
+	}
+	return results
+}
+
+// columnChanges returns changes AutoMigrate will apply to field's column, fields with tag `noalter` are skipped
+func (m Migrator) columnChanges(field *schema.Field, columnType *sql.ColumnType, attrs map[string]columnAttributes) (changes []ColumnChange) {
+	if _, ok := field.TagSettings["NOALTER"]; !ok {
+		changes = m.ColumnChanges(field, columnType)
+		if columnAttrs, ok := attrs[strings.ToLower(field.DBName)]; ok {
+			if _, ok := m.columnNullable(columnType); ok {
+				// nullability has been compared with the column type
+				columnAttrs.Nullable.Valid = false
+			}
+			changes = append(changes, m.attributeChanges(field, columnAttrs)...)
+		}
+	}
+	return
+}
+
+// attributeChanges compare field's default value, comment and nullability with column's attributes
+func (m Migrator) attributeChanges(field *schema.Field, attrs columnAttributes) (changes []ColumnChange) {
+	if field.HasDefaultValue && field.DefaultValue != "" && !field.AutoIncrement {
+		var changed bool
+		if value, ok := field.DefaultValueInterface.(bool); ok {
+			// boolean defaults are reported as 1, true or t
+			dbValue, err := strconv.ParseBool(normalizeDefaultValue(attrs.Default.String))
+			changed = err != nil || dbValue != value
+		} else {
+			changed = normalizeDefaultValue(field.DefaultValue) != normalizeDefaultValue(attrs.Default.String)
+		}
+
+		if changed {
+			changes = append(changes, ColumnChange{Attribute: "default", From: attrs.Default.String, To: field.DefaultValue})
+		}
+	}
+
+	if attrs.Comment.Valid && field.Comment != attrs.Comment.String {
+		changes = append(changes, ColumnChange{Attribute: "comment", From: attrs.Comment.String, To: field.Comment})
+	}
+
+	if attrs.Nullable.Valid && !field.PrimaryKey && attrs.Nullable.Bool == field.NotNull {
+		changes = append(changes, nullableChange(field, attrs.Nullable.Bool))
+	}
+	return
+}
+
+// normalizeDataType returns data type's name without size and modifiers, e.g: `bigint(20) unsigned`, `UNSIGNED BIGINT` -> `bigint`
+func normalizeDataType(dataType string) string {
+	dataType = strings.TrimPrefix(strings.ToLower(dataType), "unsigned ")
+	if start, end := strings.Index(dataType, "("), strings.Index(dataType, ")"); start >= 0 && end > start {
+		dataType = dataType[:start] + dataType[end+1:]
+	}
+
+	for _, modifier := range dataTypeModifiers {
+		if idx := strings.Index(dataType, modifier); idx >= 0 {
+			dataType = dataType[:idx]
+		}
+	}
+
+	dataType = strings.TrimSpace(dataType)
+	if alias, ok := dataTypeAliases[dataType]; ok {
+		return alias
+	}
+	return dataType
+}
+
+// isWideningDataType returns true if data type from could be converted to data type to without losing data
+func isWideningDataType(from, to string) bool {
+	for _, types := range dataTypeWidenings {
+		fromIdx, toIdx := -1, -1
+		for idx, dataType := range types {
+			if dataType == from {
+				fromIdx = idx
+			} else if dataType == to {
+				toIdx = idx
+			}
+		}
+
+		if fromIdx >= 0 && toIdx >= 0 {
+			return fromIdx < toIdx
+		}
+	}
+	return false
+}
+
+// normalizeDefaultValue returns default value without quotes, parentheses and type casts, e.g: `('hello')`, `'hello'::text` -> `hello`
+func normalizeDefaultValue(value string) string {
+	if idx := strings.LastIndex(value, "::"); idx > 0 {
+		value = value[:idx]
+	}
+	return strings.ToLower(strings.Trim(value, "()'\" "))
+}
+
+// recordConnPool record executed SQL instead of executing them, queries are executed with the underlying connection pool
+type recordConnPool struct {
+	gorm.ConnPool
+	dialector gorm.Dialector
+	sqls      []string
+}
+
+func (pool *recordConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	pool.sqls = append(pool.sqls, pool.dialector.Explain(query, args...))
+	return driver.RowsAffected(0), nil
+}
+
+func (pool *recordConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return pool, nil
+}
+
+func (pool *recordConnPool) Commit() error {
+	return nil
+}
+
+func (pool *recordConnPool) Rollback() error {
+	return nil
+}
@@ -1,17 +1,36 @@
 package gorm
 
 import (
+	"container/list"
 	"context"
 	"database/sql"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 )
 
 type PreparedStmtDB struct {
-	Stmts map[string]*sql.Stmt		//多个?
-	mux   sync.RWMutex
+	Stmts map[string]*sql.Stmt //多个?
+	// MaxSize max cached statements, least recently used statements will be evicted and closed, unlimited if not positive
+	MaxSize int
+	mux     sync.Mutex
+	lru     *list.List
+	elems   map[string]*list.Element
+	refs    map[*sql.Stmt]int
+	stats   PreparedStmtStats
 	ConnPool
 }
 
+// PreparedStmtStats statistics of prepared statement cache
+type PreparedStmtStats struct {
+	Size      int
+	Hits      int64
+	Misses    int64
+	Evictions int64
+}
+
 func (db *PreparedStmtDB) Close() {
 	db.mux.Lock()
 	for k, stmt := range db.Stmts {
@@ -19,93 +38,298 @@ func (db *PreparedStmtDB) Close() {
 		delete(db.Stmts, k)
 		stmt.Close()
 	}
+	db.lru, db.elems, db.refs = nil, nil, nil
 
 	db.mux.Unlock()
 }
 
-func (db *PreparedStmtDB) prepare(query string) (*sql.Stmt, error) {
-	db.mux.RLock()
-	if stmt, ok := db.Stmts[query]; ok {
-		db.mux.RUnlock()
-		return stmt, nil
-	}
-	db.mux.RUnlock()
+// Stats returns statistics of cached statements
+func (db *PreparedStmtDB) Stats() PreparedStmtStats {
+	db.mux.Lock()
+	stats := db.stats
+	stats.Size = len(db.Stmts)
+	db.mux.Unlock()
+	return stats
+}
 
+// prepare returns the cached statement of query or prepares a new one, the statement is referenced until release is called,
+// so it won't be closed by evictions before being executed
+func (db *PreparedStmtDB) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
 	db.mux.Lock()
-	// double check
+	defer db.mux.Unlock()
+
+	if db.lru == nil {
+		db.lru, db.elems, db.refs = list.New(), map[string]*list.Element{}, map[*sql.Stmt]int{}
+	}
+
 	if stmt, ok := db.Stmts[query]; ok {
-		db.mux.Unlock()
+		db.stats.Hits++
+		if elem, ok := db.elems[query]; ok {
+			db.lru.MoveToFront(elem)
+		} else {
+			db.elems[query] = db.lru.PushFront(query)
+		}
+		db.refs[stmt]++
 		return stmt, nil
 	}
 
 	//预先准备一条语句
-	stmt, err := db.ConnPool.PrepareContext(context.Background(), query)
+	db.stats.Misses++
+	stmt, err := db.ConnPool.PrepareContext(ctx, query)
 	if err == nil {
 		db.Stmts[query] = stmt
+		db.elems[query] = db.lru.PushFront(query)
+		db.refs[stmt]++
+
+		for db.MaxSize > 0 && len(db.Stmts) > db.MaxSize {
+			db.stats.Evictions++
+			db.remove(db.lru.Back().Value.(string))
+		}
 	}
-	db.mux.Unlock()
 
 	//透传返回
 	return stmt, err
 }
 
+// release dereferences the statement returned by prepare, closes it if it has been removed from cache and not used anymore
+func (db *PreparedStmtDB) release(query string, stmt *sql.Stmt) {
+	db.mux.Lock()
+	if db.refs != nil {
+		if db.refs[stmt]--; db.refs[stmt] <= 0 {
+			delete(db.refs, stmt)
+			if cached, ok := db.Stmts[query]; !ok || cached != stmt {
+				stmt.Close()
+			}
+		}
+	}
+	db.mux.Unlock()
+}
+
+// evict removes the statement from cache if it is the cached one for query, so it will be prepared again
+func (db *PreparedStmtDB) evict(query string, stmt *sql.Stmt) {
+	db.mux.Lock()
+	if cached, ok := db.Stmts[query]; ok && cached == stmt {
+		db.stats.Evictions++
+		db.remove(query)
+	}
+	db.mux.Unlock()
+}
+
+func (db *PreparedStmtDB) remove(query string) {
+	if stmt, ok := db.Stmts[query]; ok {
+		delete(db.Stmts, query)
+		// statements being used will be closed when released
+		if db.refs[stmt] <= 0 {
+			stmt.Close()
+		}
+	}
+
+	if elem, ok := db.elems[query]; ok {
+		delete(db.elems, query)
+		db.lru.Remove(elem)
+	}
+}
+
+// isInvalidStmtError returns true if the prepared statement is invalidated by the database, e.g: schema changed, and should be prepared again
+func isInvalidStmtError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if code, ok := errorCode(err); ok {
+		return invalidStmtErrorCodes[code]
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, keyword := range []string{
+		"sql: statement is closed",                   // database/sql
+		"prepared statement needs to be re-prepared", // MySQL 1615
+		"unknown prepared statement handler",         // MySQL 1243
+		"cached plan must not change result type",    // PostgreSQL 0A000
+		"database schema has changed",                // SQLite SQLITE_SCHEMA
+	} {
+		if strings.Contains(msg, keyword) {
+			return true
+		}
+	}
+
+	// PostgreSQL 26000, e.g: prepared statement "stmtcache_1" does not exist
+	return strings.Contains(msg, "prepared statement \"") && strings.HasSuffix(msg, "does not exist")
+}
+
+// invalidStmtErrorCodes error codes of invalidated prepared statements
+var invalidStmtErrorCodes = map[string]bool{
+	"1615":  true, // MySQL ER_NEED_REPREPARE
+	"1243":  true, // MySQL ER_UNKNOWN_STMT_HANDLER
+	"0A000": true, // PostgreSQL feature_not_supported, e.g: cached plan must not change result type
+	"26000": true, // PostgreSQL invalid_sql_statement_name
+}
+
+// errorCode returns the error code reported by the driver, e.g: Number of MySQL errors, SQLSTATE Code of PostgreSQL errors
+func errorCode(err error) (string, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(interface{ SQLState() string }); ok {
+			return e.SQLState(), true
+		}
+
+		if rv := reflect.Indirect(reflect.ValueOf(err)); rv.Kind() == reflect.Struct {
+			if field := rv.FieldByName("Number"); field.IsValid() {
+				switch field.Kind() {
+				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+					return strconv.FormatUint(field.Uint(), 10), true
+				}
+			}
+
+			if field := rv.FieldByName("Code"); field.IsValid() && field.Kind() == reflect.String {
+				return field.String(), true
+			}
+		}
+	}
+	return "", false
+}
+
 func (db *PreparedStmtDB) BeginTx(ctx context.Context, opt *sql.TxOptions) (ConnPool, error) {
-	if beginner, ok := db.ConnPool.(TxBeginner); ok {
+	switch beginner := db.ConnPool.(type) {
+	case TxBeginner:
 		tx, err := beginner.BeginTx(ctx, opt)
 		return &PreparedStmtTX{PreparedStmtDB: db, Tx: tx}, err
+	case ConnPoolBeginner:
+		// customized conn pools, e.g: resolver, start transactions by themselves
+		return beginner.BeginTx(ctx, opt)
 	}
 	return nil, ErrInvalidTransaction
 }
 
-func (db *PreparedStmtDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	stmt, err := db.prepare(query)
-	if err == nil {
-		return stmt.ExecContext(ctx, args...)
+func (db *PreparedStmtDB) ExecContext(ctx context.Context, query string, args ...interface{}) (result sql.Result, err error) {
+	for retried := false; ; retried = true {
+		var stmt *sql.Stmt
+		if stmt, err = db.prepare(ctx, query); err != nil {
+			return nil, err
+		}
+
+		result, err = stmt.ExecContext(ctx, args...)
+		db.release(query, stmt)
+		if retried || !isInvalidStmtError(err) {
+			return result, err
+		}
+		db.evict(query, stmt)
 	}
-	return nil, err
 }
 
-func (db *PreparedStmtDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	stmt, err := db.prepare(query)
-	if err == nil {
-		return stmt.QueryContext(ctx, args...)
+func (db *PreparedStmtDB) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	for retried := false; ; retried = true {
+		var stmt *sql.Stmt
+		if stmt, err = db.prepare(ctx, query); err != nil {
+			return nil, err
+		}
+
+		// opened rows keep the statement open until they are closed
+		rows, err = stmt.QueryContext(ctx, args...)
+		db.release(query, stmt)
+		if retried || !isInvalidStmtError(err) {
+			return rows, err
+		}
+		db.evict(query, stmt)
 	}
-	return nil, err
 }
 
-func (db *PreparedStmtDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	stmt, err := db.prepare(query)
-	if err == nil {
-		return stmt.QueryRowContext(ctx, args...)
+func (db *PreparedStmtDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) (row *sql.Row) {
+	for retried := false; ; retried = true {
+		stmt, err := db.prepare(ctx, query)
+		if err != nil {
+			// query without preparing, so the error will be returned when scanning the row
+			return db.ConnPool.QueryRowContext(ctx, query, args...)
+		}
+
+		row = stmt.QueryRowContext(ctx, args...)
+		db.release(query, stmt)
+		if retried || !isInvalidStmtError(rowErr(row)) {
+			return row
+		}
+		db.evict(query, stmt)
+	}
+}
+
+// rowErr returns the deferred error of row, which could only be checked with Go 1.15+ before scanning
+func rowErr(row *sql.Row) error {
+	if r, ok := interface{}(row).(interface{ Err() error }); ok {
+		return r.Err()
 	}
-	return &sql.Row{}
+	return nil
 }
 
 type PreparedStmtTX struct {
 	*sql.Tx
 	PreparedStmtDB *PreparedStmtDB
+	mux            sync.Mutex
+	stmts          map[string]*sql.Stmt
+	parents        map[string]*sql.Stmt
+}
+
+// prepare returns the cached statement of the PreparedStmtDB bound to the transaction, it is cached until the transaction finished
+func (tx *PreparedStmtTX) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	tx.mux.Lock()
+	defer tx.mux.Unlock()
+
+	if stmt, ok := tx.stmts[query]; ok {
+		return stmt, nil
+	}
+
+	// the statement is referenced until the transaction finished, so it won't be closed by evictions
+	parent, err := tx.PreparedStmtDB.prepare(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.stmts == nil {
+		tx.stmts, tx.parents = map[string]*sql.Stmt{}, map[string]*sql.Stmt{}
+	}
+	stmt := tx.Tx.StmtContext(ctx, parent)
+	tx.stmts[query], tx.parents[query] = stmt, parent
+	return stmt, nil
+}
+
+// release releases statements of the PreparedStmtDB used by the transaction
+func (tx *PreparedStmtTX) release() {
+	tx.mux.Lock()
+	for query, parent := range tx.parents {
+		tx.PreparedStmtDB.release(query, parent)
+	}
+	tx.stmts, tx.parents = nil, nil
+	tx.mux.Unlock()
+}
+
+func (tx *PreparedStmtTX) Commit() error {
+	defer tx.release()
+	return tx.Tx.Commit()
+}
+
+func (tx *PreparedStmtTX) Rollback() error {
+	defer tx.release()
+	return tx.Tx.Rollback()
 }
 
 func (tx *PreparedStmtTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
-	stmt, err := tx.PreparedStmtDB.prepare(query)
+	stmt, err := tx.prepare(ctx, query)
 	if err == nil {
-		return tx.Tx.Stmt(stmt).ExecContext(ctx, args...)
+		return stmt.ExecContext(ctx, args...)
 	}
 	return nil, err
 }
 
 func (tx *PreparedStmtTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
-	stmt, err := tx.PreparedStmtDB.prepare(query)
+	stmt, err := tx.prepare(ctx, query)
 	if err == nil {
-		return tx.Tx.Stmt(stmt).QueryContext(ctx, args...)
+		return stmt.QueryContext(ctx, args...)
 	}
 	return nil, err
 }
 
 func (tx *PreparedStmtTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
-	stmt, err := tx.PreparedStmtDB.prepare(query)
+	stmt, err := tx.prepare(ctx, query)
 	if err == nil {
-		return tx.Tx.Stmt(stmt).QueryRowContext(ctx, args...)
+		return stmt.QueryRowContext(ctx, args...)
 	}
-	return &sql.Row{}
+	// query without preparing, so the error will be returned when scanning the row
+	return tx.Tx.QueryRowContext(ctx, query, args...)
 }
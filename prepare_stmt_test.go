@@ -0,0 +1,62 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type mysqlError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *mysqlError) Error() string {
+	return fmt.Sprintf("Error %d: %s", e.Number, e.Message)
+}
+
+type pqError struct {
+	Code    string
+	Message string
+}
+
+func (e *pqError) Error() string {
+	return "pq: " + e.Message
+}
+
+type pgError struct {
+	Code    string
+	Message string
+}
+
+func (e *pgError) Error() string {
+	return "ERROR: " + e.Message
+}
+
+func (e *pgError) SQLState() string {
+	return e.Code
+}
+
+func TestIsInvalidStmtError(t *testing.T) {
+	tests := []struct {
+		err     error
+		invalid bool
+	}{
+		{err: &mysqlError{Number: 1615, Message: "Prepared statement needs to be re-prepared"}, invalid: true},
+		{err: &mysqlError{Number: 1243, Message: "Unknown prepared statement handler"}, invalid: true},
+		{err: &mysqlError{Number: 1062, Message: "Duplicate entry, prepared statement needs to be re-prepared"}, invalid: false},
+		{err: &pqError{Code: "0A000", Message: "cached plan must not change result type"}, invalid: true},
+		{err: &pqError{Code: "23505", Message: "duplicate key value violates unique constraint"}, invalid: false},
+		{err: fmt.Errorf("wrapped: %w", &pgError{Code: "26000", Message: "prepared statement \"stmt_1\" does not exist"}), invalid: true},
+		{err: &pgError{Code: "42P01", Message: "relation \"users\" does not exist"}, invalid: false},
+		{err: errors.New("sql: statement is closed"), invalid: true},
+		{err: errors.New("database schema has changed"), invalid: true},
+		{err: errors.New("no such table: users"), invalid: false},
+	}
+
+	for _, test := range tests {
+		if isInvalidStmtError(test.err) != test.invalid {
+			t.Errorf("isInvalidStmtError(%v) should be %v", test.err, test.invalid)
+		}
+	}
+}
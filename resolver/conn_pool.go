@@ -0,0 +1,58 @@
+package resolver
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// ConnPool resolver's connection pool, used as db's default connection pool, operations not routed by callbacks are executed in source
+type ConnPool struct {
+	resolver *Resolver
+}
+
+// source returns source conn pool for operations not routed by callbacks
+func (pool *ConnPool) source() gorm.ConnPool {
+	return pool.resolver.prepared(pool.resolver.resolve(nil, Write), pool.resolver.prepareStmt)
+}
+
+func (pool *ConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return pool.source().PrepareContext(ctx, query)
+}
+
+func (pool *ConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return pool.source().ExecContext(ctx, query, args...)
+}
+
+func (pool *ConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return pool.source().QueryContext(ctx, query, args...)
+}
+
+func (pool *ConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return pool.source().QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx start transaction in source
+func (pool *ConnPool) BeginTx(ctx context.Context, opt *sql.TxOptions) (gorm.ConnPool, error) {
+	switch conn := pool.source().(type) {
+	case gorm.TxBeginner:
+		return conn.BeginTx(ctx, opt)
+	case gorm.ConnPoolBeginner:
+		return conn.BeginTx(ctx, opt)
+	default:
+		return nil, gorm.ErrInvalidTransaction
+	}
+}
+
+// Ping ping sources and replicas
+func (pool *ConnPool) Ping() error {
+	for connPool := range pool.resolver.connPools {
+		if pinger, ok := connPool.(interface{ Ping() error }); ok && connPool != pool {
+			if err := pinger.Ping(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package resolver
+
+import (
+	"math/rand"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// Policy load balancing policy, choose one connection pool from sources or replicas
+type Policy interface {
+	Resolve([]gorm.ConnPool) gorm.ConnPool
+}
+
+// PolicyFunc func type implements Policy
+type PolicyFunc func([]gorm.ConnPool) gorm.ConnPool
+
+func (f PolicyFunc) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	return f(connPools)
+}
+
+// RandomPolicy choose connection pool randomly
+type RandomPolicy struct {
+}
+
+func (RandomPolicy) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	return connPools[rand.Intn(len(connPools))]
+}
+
+// RoundRobinPolicy choose connection pools in turn
+func RoundRobinPolicy() Policy {
+	var idx uint64
+	return PolicyFunc(func(connPools []gorm.ConnPool) gorm.ConnPool {
+		return connPools[(atomic.AddUint64(&idx, 1)-1)%uint64(len(connPools))]
+	})
+}
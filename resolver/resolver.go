@@ -0,0 +1,231 @@
+package resolver
+
+import (
+	"database/sql"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Operation specify the operation's connection type, e.g: db.Clauses(resolver.Write).First(&user) query user from source
+type Operation string
+
+const (
+	Write Operation = "write"
+	Read  Operation = "read"
+)
+
+// Name clause name, operation won't be built into SQL as no processor builds it
+func (op Operation) Name() string {
+	return "gorm:resolver"
+}
+
+func (op Operation) Build(clause.Builder) {
+}
+
+func (op Operation) MergeClause(c *clause.Clause) {
+	c.Expression = op
+}
+
+// Config sources and replicas for resolver, the default connection pool will be used as source if no source specified
+type Config struct {
+	Sources  []gorm.Dialector
+	Replicas []gorm.Dialector
+	Policy   Policy
+}
+
+type registration struct {
+	config Config
+	datas  []interface{}
+}
+
+// Resolver read/write splitting plugin
+type Resolver struct {
+	registrations []registration
+	global        *resolver
+	resolvers     map[string]*resolver
+	connPools     map[gorm.ConnPool]bool
+	connPool      *ConnPool
+	prepareStmt   bool
+	maxStmts      int
+	mux           sync.Mutex
+	preparedStmts map[gorm.ConnPool]*gorm.PreparedStmtDB
+}
+
+type resolver struct {
+	sources  []gorm.ConnPool
+	replicas []gorm.ConnPool
+	policy   Policy
+}
+
+// Register register config for resolver, datas could be models or table names, config without datas will be used as default
+func Register(config Config, datas ...interface{}) *Resolver {
+	return (&Resolver{}).Register(config, datas...)
+}
+
+// Register register config for resolver
+func (r *Resolver) Register(config Config, datas ...interface{}) *Resolver {
+	r.registrations = append(r.registrations, registration{config: config, datas: datas})
+	return r
+}
+
+// Name plugin name
+func (r *Resolver) Name() string {
+	return "gorm:resolver"
+}
+
+// Initialize open sources and replicas, register callbacks to route statements
+func (r *Resolver) Initialize(db *gorm.DB) (err error) {
+	connPool := db.ConnPool
+	if stmtDB, ok := connPool.(*gorm.PreparedStmtDB); ok {
+		// statements are prepared with the routed conn pools instead
+		connPool = stmtDB.ConnPool
+	}
+
+	r.resolvers = map[string]*resolver{}
+	r.connPools = map[gorm.ConnPool]bool{connPool: true}
+	r.global = &resolver{sources: []gorm.ConnPool{connPool}, policy: RandomPolicy{}}
+	r.prepareStmt, r.maxStmts = db.PrepareStmt, db.PrepareStmtMaxSize
+	r.preparedStmts = map[gorm.ConnPool]*gorm.PreparedStmtDB{}
+
+	for _, reg := range r.registrations {
+		if len(reg.datas) == 0 {
+			if r.global, err = r.compile(db, reg.config, r.global); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, reg := range r.registrations {
+		if len(reg.datas) > 0 {
+			res, err := r.compile(db, reg.config, r.global)
+			if err != nil {
+				return err
+			}
+
+			for _, data := range reg.datas {
+				if table, ok := data.(string); ok {
+					r.resolvers[table] = res
+				} else {
+					stmt := &gorm.Statement{DB: db}
+					if err := stmt.Parse(data); err != nil {
+						return err
+					}
+					r.resolvers[stmt.Table] = res
+				}
+			}
+		}
+	}
+
+	r.registerCallbacks(db)
+
+	r.connPool = &ConnPool{resolver: r}
+	r.connPools[r.connPool] = true
+	db.ConnPool = r.connPool
+	db.Statement.ConnPool = r.connPool
+	return nil
+}
+
+func (r *Resolver) compile(db *gorm.DB, config Config, defaultResolver *resolver) (res *resolver, err error) {
+	res = &resolver{policy: config.Policy}
+
+	if res.sources, err = r.openConnPools(db, config.Sources); err != nil {
+		return nil, err
+	} else if len(res.sources) == 0 {
+		res.sources = defaultResolver.sources
+	}
+
+	if res.replicas, err = r.openConnPools(db, config.Replicas); err != nil {
+		return nil, err
+	} else if len(res.replicas) == 0 {
+		res.replicas = defaultResolver.replicas
+	}
+
+	if res.policy == nil {
+		res.policy = defaultResolver.policy
+	}
+	return res, nil
+}
+
+func (r *Resolver) openConnPools(db *gorm.DB, dialectors []gorm.Dialector) (connPools []gorm.ConnPool, err error) {
+	for _, dialector := range dialectors {
+		conn, err := gorm.Open(dialector, &gorm.Config{
+			Logger:               db.Logger,
+			DisableAutomaticPing: db.DisableAutomaticPing,
+		})
+		if err != nil {
+			return nil, err
+		}
+		connPools = append(connPools, conn.ConnPool)
+		r.connPools[conn.ConnPool] = true
+	}
+	return
+}
+
+func (r *Resolver) registerCallbacks(db *gorm.DB) {
+	db.Callback().Create().Before("gorm:begin_transaction").Register("gorm:resolver", r.switchSource)
+	db.Callback().Update().Before("gorm:begin_transaction").Register("gorm:resolver", r.switchSource)
+	db.Callback().Delete().Before("gorm:begin_transaction").Register("gorm:resolver", r.switchSource)
+	db.Callback().Raw().Before("gorm:raw").Register("gorm:resolver", r.switchSource)
+	db.Callback().Query().Before("gorm:query").Register("gorm:resolver", r.switchReplica)
+	db.Callback().Row().Before("gorm:raw").Register("gorm:resolver", r.switchReplica)
+}
+
+// switchSource route statement to source, statements in transaction won't be routed as they are not using resolver's connection pools
+func (r *Resolver) switchSource(db *gorm.DB) {
+	if prepared, ok := r.routable(db.Statement.ConnPool); ok {
+		db.Statement.ConnPool = r.prepared(r.resolve(db.Statement, Write), prepared)
+	}
+}
+
+// switchReplica route statement to replica, unless it is required to be written
+func (r *Resolver) switchReplica(db *gorm.DB) {
+	if prepared, ok := r.routable(db.Statement.ConnPool); ok {
+		if c, ok := db.Statement.Clauses["gorm:resolver"]; ok && c.Expression == Write {
+			db.Statement.ConnPool = r.prepared(r.resolve(db.Statement, Write), prepared)
+		} else {
+			db.Statement.ConnPool = r.prepared(r.resolve(db.Statement, Read), prepared)
+		}
+	}
+}
+
+// routable returns true if the conn pool is resolver's one, conn pools wrapped by PreparedStmtDB are unwrapped and routed with prepared statements,
+// transactions, including the ones wrapped by PreparedStmtTX, are not routed
+func (r *Resolver) routable(connPool gorm.ConnPool) (prepared bool, ok bool) {
+	if stmtDB, isPrepared := connPool.(*gorm.PreparedStmtDB); isPrepared {
+		return true, r.connPools[stmtDB.ConnPool]
+	}
+	return r.prepareStmt, r.connPools[connPool]
+}
+
+// prepared returns conn pool that caches prepared statements of the routed conn pool if required
+func (r *Resolver) prepared(connPool gorm.ConnPool, prepared bool) gorm.ConnPool {
+	if !prepared {
+		return connPool
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	stmtDB, ok := r.preparedStmts[connPool]
+	if !ok {
+		stmtDB = &gorm.PreparedStmtDB{ConnPool: connPool, Stmts: map[string]*sql.Stmt{}, MaxSize: r.maxStmts}
+		r.preparedStmts[connPool] = stmtDB
+	}
+	return stmtDB
+}
+
+func (r *Resolver) resolve(stmt *gorm.Statement, op Operation) gorm.ConnPool {
+	res := r.global
+	if stmt != nil {
+		if v, ok := r.resolvers[stmt.Table]; ok {
+			res = v
+		}
+	}
+
+	if op == Read && len(res.replicas) > 0 {
+		return res.policy.Resolve(res.replicas)
+	}
+	return res.policy.Resolve(res.sources)
+}
@@ -86,22 +86,6 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 
 	fieldValue := reflect.New(field.IndirectFieldType)
 
-	if fc, ok := fieldValue.Interface().(CreateClausesInterface); ok {
-		field.Schema.CreateClauses = append(field.Schema.CreateClauses, fc.CreateClauses()...)
-	}
-
-	if fc, ok := fieldValue.Interface().(QueryClausesInterface); ok {
-		field.Schema.QueryClauses = append(field.Schema.QueryClauses, fc.QueryClauses()...)
-	}
-
-	if fc, ok := fieldValue.Interface().(UpdateClausesInterface); ok {
-		field.Schema.UpdateClauses = append(field.Schema.UpdateClauses, fc.UpdateClauses()...)
-	}
-
-	if fc, ok := fieldValue.Interface().(DeleteClausesInterface); ok {
-		field.Schema.DeleteClauses = append(field.Schema.DeleteClauses, fc.DeleteClauses()...)
-	}
-
 	// if field is valuer, used its value or first fields as data type
 	valuer, isValuer := fieldValue.Interface().(driver.Valuer)
 	if isValuer {
@@ -328,16 +312,44 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 				ef.TagSettings[k] = v
 			}
 		}
-
-		field.Schema.CreateClauses = append(field.Schema.CreateClauses, field.EmbeddedSchema.CreateClauses...)
-		field.Schema.QueryClauses = append(field.Schema.QueryClauses, field.EmbeddedSchema.QueryClauses...)
-		field.Schema.UpdateClauses = append(field.Schema.UpdateClauses, field.EmbeddedSchema.UpdateClauses...)
-		field.Schema.DeleteClauses = append(field.Schema.DeleteClauses, field.EmbeddedSchema.DeleteClauses...)
 	}
 
 	return field
 }
 
+// setupClauses register field's clauses to schema, clauses are created with the parsed field
+func (field *Field) setupClauses() {
+	fieldValue := reflect.New(field.IndirectFieldType).Interface()
+
+	switch fc := fieldValue.(type) {
+	case FieldCreateClausesInterface:
+		field.Schema.CreateClauses = append(field.Schema.CreateClauses, fc.CreateClauses(field)...)
+	case CreateClausesInterface:
+		field.Schema.CreateClauses = append(field.Schema.CreateClauses, fc.CreateClauses()...)
+	}
+
+	switch fc := fieldValue.(type) {
+	case FieldQueryClausesInterface:
+		field.Schema.QueryClauses = append(field.Schema.QueryClauses, fc.QueryClauses(field)...)
+	case QueryClausesInterface:
+		field.Schema.QueryClauses = append(field.Schema.QueryClauses, fc.QueryClauses()...)
+	}
+
+	switch fc := fieldValue.(type) {
+	case FieldUpdateClausesInterface:
+		field.Schema.UpdateClauses = append(field.Schema.UpdateClauses, fc.UpdateClauses(field)...)
+	case UpdateClausesInterface:
+		field.Schema.UpdateClauses = append(field.Schema.UpdateClauses, fc.UpdateClauses()...)
+	}
+
+	switch fc := fieldValue.(type) {
+	case FieldDeleteClausesInterface:
+		field.Schema.DeleteClauses = append(field.Schema.DeleteClauses, fc.DeleteClauses(field)...)
+	case DeleteClausesInterface:
+		field.Schema.DeleteClauses = append(field.Schema.DeleteClauses, fc.DeleteClauses()...)
+	}
+}
+
 // create valuer, setter when parse struct
 func (field *Field) setupValuerAndSetter() {
 	// ValueOf
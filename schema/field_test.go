@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 	"gorm.io/gorm/utils/tests"
 )
@@ -249,3 +250,36 @@ func TestParseFieldWithPermission(t *testing.T) {
 		checkSchemaField(t, user, &f, func(f *schema.Field) {})
 	}
 }
+
+type legacyClausesField int
+
+func (legacyClausesField) QueryClauses() []clause.Interface {
+	return []clause.Interface{clause.Where{}}
+}
+
+type fieldClausesField int
+
+func (fieldClausesField) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{clause.Where{Exprs: []clause.Expression{clause.Eq{Column: f.DBName}}}}
+}
+
+func TestParseFieldClauses(t *testing.T) {
+	type ClausesModel struct {
+		ID     uint
+		Legacy legacyClausesField
+		Field  fieldClausesField
+	}
+
+	s, err := schema.Parse(&ClausesModel{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse model with clauses fields, got error %v", err)
+	}
+
+	if len(s.QueryClauses) != 2 {
+		t.Fatalf("clauses of both interfaces should be registered, got %+v", s.QueryClauses)
+	}
+
+	if where, ok := s.QueryClauses[1].(clause.Where); !ok || len(where.Exprs) != 1 || where.Exprs[0].(clause.Eq).Column != "field" {
+		t.Errorf("clauses should be created with the parsed field, got %+v", s.QueryClauses[1])
+	}
+}
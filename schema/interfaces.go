@@ -21,3 +21,23 @@ type UpdateClausesInterface interface {
 type DeleteClausesInterface interface {
 	DeleteClauses() []clause.Interface
 }
+
+// FieldCreateClausesInterface like CreateClausesInterface, clauses are created with the parsed field
+type FieldCreateClausesInterface interface {
+	CreateClauses(*Field) []clause.Interface
+}
+
+// FieldQueryClausesInterface like QueryClausesInterface, clauses are created with the parsed field
+type FieldQueryClausesInterface interface {
+	QueryClauses(*Field) []clause.Interface
+}
+
+// FieldUpdateClausesInterface like UpdateClausesInterface, clauses are created with the parsed field
+type FieldUpdateClausesInterface interface {
+	UpdateClauses(*Field) []clause.Interface
+}
+
+// FieldDeleteClausesInterface like DeleteClausesInterface, clauses are created with the parsed field
+type FieldDeleteClausesInterface interface {
+	DeleteClauses(*Field) []clause.Interface
+}
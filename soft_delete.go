@@ -4,11 +4,13 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"reflect"
+	"time"
 
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
 
+// DeletedAt soft delete with nullable timestamp, NULL means not deleted
 type DeletedAt sql.NullTime
 
 // Scan implements the Scanner interface.
@@ -24,22 +26,192 @@ func (n DeletedAt) Value() (driver.Value, error) {
 	return n.Time, nil
 }
 
-func (DeletedAt) QueryClauses() []clause.Interface {
-	return []clause.Interface{
-		clause.Where{Exprs: []clause.Expression{
-			clause.Eq{
-				Column: clause.Column{Table: clause.CurrentTable, Name: "deleted_at"},
-				Value:  nil,
-			},
-		}},
+func (DeletedAt) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f}}
+}
+
+func (DeletedAt) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteClause{Field: f}}
+}
+
+// DeletedFlag soft delete with bool flag, e.g: is_deleted
+type DeletedFlag bool
+
+// Scan implements the Scanner interface.
+func (n *DeletedFlag) Scan(value interface{}) error {
+	var flag sql.NullBool
+	err := flag.Scan(value)
+	*n = DeletedFlag(flag.Bool)
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n DeletedFlag) Value() (driver.Value, error) {
+	return bool(n), nil
+}
+
+func (DeletedFlag) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f}}
+}
+
+func (DeletedFlag) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteClause{Field: f}}
+}
+
+// DeletedFlagAt soft delete with bool flag, deleting time will be saved to the field specified by tag `deletedAtField`, default is `deleted_at`
+//   IsDeleted gorm.DeletedFlagAt `gorm:"deletedAtField:RemovedAt"`
+//   RemovedAt *time.Time
+type DeletedFlagAt bool
+
+// Scan implements the Scanner interface.
+func (n *DeletedFlagAt) Scan(value interface{}) error {
+	var flag sql.NullBool
+	err := flag.Scan(value)
+	*n = DeletedFlagAt(flag.Bool)
+	return err
+}
+
+// Value implements the driver Valuer interface.
+func (n DeletedFlagAt) Value() (driver.Value, error) {
+	return bool(n), nil
+}
+
+func (DeletedFlagAt) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f}}
+}
+
+func (DeletedFlagAt) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteClause{Field: f}}
+}
+
+// DeletedUnix soft delete with unix seconds, 0 means not deleted, so it could be used in unique indexes
+type DeletedUnix int64
+
+func (DeletedUnix) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f}}
+}
+
+func (DeletedUnix) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteClause{Field: f}}
+}
+
+// DeletedUnixMilli soft delete with unix milliseconds, 0 means not deleted, so it could be used in unique indexes
+type DeletedUnixMilli int64
+
+func (DeletedUnixMilli) QueryClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteQueryClause{Field: f}}
+}
+
+func (DeletedUnixMilli) DeleteClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{SoftDeleteClause{Field: f}}
+}
+
+// softDeleteValues returns field's values for deleted and not deleted records
+func softDeleteValues(field *schema.Field, now time.Time) (deleted interface{}, notDeleted interface{}) {
+	switch reflect.New(field.IndirectFieldType).Interface().(type) {
+	case *DeletedFlag, *DeletedFlagAt:
+		return true, false
+	case *DeletedUnix:
+		return now.Unix(), 0
+	case *DeletedUnixMilli:
+		return now.UnixNano() / int64(time.Millisecond), 0
+	default:
+		return now, nil
+	}
+}
+
+// softDeleteField returns the soft delete field of schema, returns nil if the schema doesn't support soft delete
+func softDeleteField(s *schema.Schema) *schema.Field {
+	if s != nil {
+		for _, c := range s.QueryClauses {
+			if sd, ok := c.(SoftDeleteQueryClause); ok {
+				return sd.Field
+			}
+		}
 	}
+	return nil
 }
 
-func (DeletedAt) DeleteClauses() []clause.Interface {
-	return []clause.Interface{SoftDeleteClause{}}
+// softDeleteAssignments returns assignments to soft delete records, or to restore them if not deleted
+func softDeleteAssignments(stmt *Statement, field *schema.Field, deleted bool) clause.Set {
+	now := stmt.DB.NowFunc()
+	deletedValue, notDeletedValue := softDeleteValues(field, now)
+
+	set := clause.Set{{Column: clause.Column{Name: field.DBName}, Value: notDeletedValue}}
+	if deleted {
+		set[0].Value = deletedValue
+	}
+
+	if _, ok := reflect.New(field.IndirectFieldType).Interface().(*DeletedFlagAt); ok {
+		name := field.TagSettings["DELETEDATFIELD"]
+		if name == "" {
+			name = "deleted_at"
+		}
+
+		if deletedAtField := field.Schema.LookUpField(name); deletedAtField != nil {
+			if deleted {
+				set = append(set, clause.Assignment{Column: clause.Column{Name: deletedAtField.DBName}, Value: now})
+			} else {
+				set = append(set, clause.Assignment{Column: clause.Column{Name: deletedAtField.DBName}, Value: nil})
+			}
+		}
+	}
+	return set
 }
 
+// SoftDeleteQueryClause query clause of soft delete field, excludes soft deleted records
+type SoftDeleteQueryClause struct {
+	Field *schema.Field
+}
+
+func (SoftDeleteQueryClause) Name() string {
+	return ""
+}
+
+func (SoftDeleteQueryClause) Build(clause.Builder) {
+}
+
+func (SoftDeleteQueryClause) MergeClause(*clause.Clause) {
+}
+
+func (sd SoftDeleteQueryClause) ModifyStatement(stmt *Statement) {
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			for _, expr := range where.Exprs {
+				if _, ok := expr.(softDeletedCondition); ok {
+					// querying soft deleted records with OnlyDeleted
+					return
+				}
+			}
+		}
+	}
+
+	_, notDeletedValue := softDeleteValues(sd.Field, time.Time{})
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sd.Field.DBName}, Value: notDeletedValue},
+	}})
+}
+
+// softDeletedCondition condition of soft deleted records, it's built with the soft delete field of statement's schema
+type softDeletedCondition struct{}
+
+func (softDeletedCondition) Build(builder clause.Builder) {
+	if stmt, ok := builder.(*Statement); ok {
+		if field := softDeleteField(stmt.Schema); field != nil {
+			_, notDeletedValue := softDeleteValues(field, time.Time{})
+			clause.Neq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: notDeletedValue}.Build(builder)
+			return
+		}
+		stmt.DB.AddError(ErrMissingSoftDeleteField)
+	}
+
+	// never matches if soft delete unsupported
+	builder.WriteString("1 <> 1")
+}
+
+// SoftDeleteClause delete clause of soft delete field, updates the field instead of deleting records
 type SoftDeleteClause struct {
+	Field *schema.Field
 }
 
 func (SoftDeleteClause) Name() string {
@@ -52,24 +224,18 @@ func (SoftDeleteClause) Build(clause.Builder) {
 func (SoftDeleteClause) MergeClause(*clause.Clause) {
 }
 
-func (SoftDeleteClause) ModifyStatement(stmt *Statement) {
+func (sd SoftDeleteClause) ModifyStatement(stmt *Statement) {
 	if stmt.SQL.String() == "" {
-		stmt.AddClause(clause.Set{{Column: clause.Column{Name: "deleted_at"}, Value: stmt.DB.NowFunc()}})
+		stmt.AddClause(softDeleteAssignments(stmt, sd.Field, true))
 
 		if stmt.Schema != nil {
-			_, queryValues := schema.GetIdentityFieldValuesMap(stmt.ReflectValue, stmt.Schema.PrimaryFields)
-			column, values := schema.ToQueryValues(stmt.Schema.PrimaryFieldDBNames, queryValues)
-
-			if len(values) > 0 {
-				stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+			if cond := primaryKeysCondition(stmt.Schema, stmt.ReflectValue); cond != nil {
+				stmt.AddClause(clause.Where{Exprs: []clause.Expression{cond}})
 			}
 
 			if stmt.Dest != stmt.Model && stmt.Model != nil {
-				_, queryValues = schema.GetIdentityFieldValuesMap(reflect.ValueOf(stmt.Model), stmt.Schema.PrimaryFields)
-				column, values = schema.ToQueryValues(stmt.Schema.PrimaryFieldDBNames, queryValues)
-
-				if len(values) > 0 {
-					stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+				if cond := primaryKeysCondition(stmt.Schema, reflect.ValueOf(stmt.Model)); cond != nil {
+					stmt.AddClause(clause.Where{Exprs: []clause.Expression{cond}})
 				}
 			}
 		}
@@ -80,6 +246,30 @@ func (SoftDeleteClause) ModifyStatement(stmt *Statement) {
 		}
 
 		stmt.AddClauseIfNotExists(clause.Update{})
-		stmt.Build("UPDATE", "SET", "WHERE")
+		stmt.Build("WITH", "UPDATE", "SET", "WHERE", "RETURNING")
+	}
+}
+
+// primaryKeysCondition returns condition matching primary keys of the records, composite primary keys are built as
+// ORed conditions of each record instead of row values, as some databases like SQLite don't support IN with row values
+func primaryKeysCondition(s *schema.Schema, reflectValue reflect.Value) clause.Expression {
+	_, queryValues := schema.GetIdentityFieldValuesMap(reflectValue, s.PrimaryFields)
+	if len(queryValues) == 0 {
+		return nil
+	}
+
+	if len(s.PrimaryFields) == 1 {
+		column, values := schema.ToQueryValues(s.PrimaryFieldDBNames, queryValues)
+		return clause.IN{Column: column, Values: values}
+	}
+
+	exprs := make([]clause.Expression, len(queryValues))
+	for idx, values := range queryValues {
+		conds := make([]clause.Expression, len(values))
+		for i, value := range values {
+			conds[i] = clause.Eq{Column: s.PrimaryFieldDBNames[i], Value: value}
+		}
+		exprs[idx] = clause.And(conds...)
 	}
+	return clause.And(clause.Or(exprs...))
 }
@@ -6,11 +6,13 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
 
 	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 )
 
@@ -18,6 +20,7 @@ import (
 type Statement struct {
 	*DB
 	Table                string			//表明
+	TableExpr            *clause.Expr
 	Model                interface{}
 	Unscoped             bool
 	Dest                 interface{}
@@ -39,6 +42,12 @@ type Statement struct {
 	NamedVars            []sql.NamedArg
 	attrs                []interface{}
 	assigns              []interface{}
+	identifiers          []clause.Column
+	exprJoins            []string
+	varColumns           []string
+	quotedColumns        []string
+	quotedVarsLen        int
+	rawExpr              *clause.Expr
 }
 
 // StatementModifier statement modifier interface
@@ -58,16 +67,59 @@ func (stmt *Statement) WriteByte(c byte) error {
 
 // WriteQuoted write quoted value
 func (stmt *Statement) WriteQuoted(value interface{}) error {
+	if len(stmt.Vars) != stmt.quotedVarsLen {
+		stmt.quotedColumns, stmt.quotedVarsLen = stmt.quotedColumns[:0], len(stmt.Vars)
+	}
+
+	switch v := value.(type) {
+	case clause.Column:
+		if v.Name == clause.PrimaryKey && stmt.Schema != nil && stmt.Schema.PrioritizedPrimaryField != nil {
+			stmt.quotedColumns = append(stmt.quotedColumns, stmt.Schema.PrioritizedPrimaryField.DBName)
+		} else {
+			stmt.quotedColumns = append(stmt.quotedColumns, v.Name)
+		}
+	case string:
+		stmt.quotedColumns = append(stmt.quotedColumns, v)
+	default:
+		stmt.quotedColumns = stmt.quotedColumns[:0]
+	}
+
 	stmt.QuoteTo(&stmt.SQL, value)
 	return nil
 }
 
+// writerBuilder builds expressions to writer, binds vars to the statement
+type writerBuilder struct {
+	*Statement
+	writer clause.Writer
+}
+
+func (w writerBuilder) WriteString(str string) (int, error) {
+	return w.writer.WriteString(str)
+}
+
+func (w writerBuilder) WriteByte(c byte) error {
+	return w.writer.WriteByte(c)
+}
+
+func (w writerBuilder) WriteQuoted(value interface{}) error {
+	w.Statement.QuoteTo(w.writer, value)
+	return nil
+}
+
 // QuoteTo write quoted value to writer
 func (stmt *Statement) QuoteTo(writer clause.Writer, field interface{}) {
 	switch v := field.(type) {
 	case clause.Table:
 		if v.Name == clause.CurrentTable {
-			stmt.DB.Dialector.QuoteTo(writer, stmt.Table)
+			if stmt.TableExpr != nil && (writer == stmt || writer == &stmt.SQL) {
+				// table expression's vars are bound to current statement
+				stmt.TableExpr.Build(stmt)
+			} else if stmt.TableExpr != nil {
+				stmt.TableExpr.Build(writerBuilder{Statement: stmt, writer: writer})
+			} else {
+				stmt.DB.Dialector.QuoteTo(writer, stmt.Table)
+			}
 		} else if v.Raw {
 			writer.WriteString(v.Name)
 		} else {
@@ -134,6 +186,11 @@ func (stmt *Statement) AddVar(writer clause.Writer, vars ...interface{}) {
 			writer.WriteByte(',')
 		}
 
+		sqlLen := -1
+		if writer == stmt || writer == &stmt.SQL {
+			sqlLen = stmt.SQL.Len()
+		}
+
 		switch v := v.(type) {
 		case sql.NamedArg:
 			if len(v.Name) > 0 {
@@ -172,11 +229,29 @@ func (stmt *Statement) AddVar(writer clause.Writer, vars ...interface{}) {
 				writer.WriteString("(NULL)")
 			}
 		case *DB:
-			subdb := v.Session(&Session{DryRun: true, WithConditions: true}).getInstance()
-			subdb.Statement.Vars = append(subdb.Statement.Vars, stmt.Vars...)
-			subdb.callbacks.Query().Execute(subdb)
-			writer.WriteString(subdb.Statement.SQL.String())
-			stmt.Vars = subdb.Statement.Vars
+			if v.Statement.rawExpr != nil {
+				// raw sql, build it with current statement, so its vars are bound after current statement's vars
+				if builder, ok := writer.(clause.Builder); ok {
+					v.Statement.rawExpr.Build(builder)
+				} else {
+					v.Statement.rawExpr.Build(stmt)
+				}
+			} else {
+				subdb := v.Session(&Session{DryRun: true, WithConditions: true}).getInstance()
+				subdb.Statement.Vars = append(subdb.Statement.Vars, stmt.Vars...)
+				subdb.callbacks.Query().Execute(subdb)
+				writer.WriteString(subdb.Statement.SQL.String())
+				stmt.Vars = subdb.Statement.Vars
+				if len(subdb.Statement.varColumns) > len(stmt.varColumns) {
+					stmt.varColumns = append(stmt.varColumns, subdb.Statement.varColumns[len(stmt.varColumns):]...)
+				}
+			}
+		case clause.Expression:
+			if builder, ok := writer.(clause.Builder); ok {
+				v.Build(builder)
+			} else {
+				v.Build(stmt)
+			}
 		default:
 			switch rv := reflect.ValueOf(v); rv.Kind() {
 			case reflect.Slice, reflect.Array:
@@ -197,9 +272,66 @@ func (stmt *Statement) AddVar(writer clause.Writer, vars ...interface{}) {
 				stmt.DB.Dialector.BindVarTo(writer, stmt, v)
 			}
 		}
+
+		if stmt.labelsVars() {
+			stmt.labelVars(idx, len(vars), sqlLen)
+		}
 	}
 }
 
+// rawVarColumnRegexp matches column and operator before bind var in SQL, e.g: `password = `, `name LIKE `, `id IN (?,`
+var rawVarColumnRegexp = regexp.MustCompile(`(?i)([\w.` + "`" + `"\[\]]+)\s*(?:=|<>|!=|<=|>=|<|>|\slike|\sin)\s*(?:\(\s*(?:[^\s,()]+\s*,\s*)*)?$`)
+
+// rawVarColumn returns column compared with the bind var at the end of SQL, blank if not found,
+// only the tail of SQL is matched, so labeling vars of large statements won't be slow
+func rawVarColumn(sql string) string {
+	tail := sql
+	if len(sql) > 256 {
+		tail = sql[len(sql)-256:]
+	}
+
+	if loc := rawVarColumnRegexp.FindStringSubmatchIndex(tail); loc != nil && (loc[2] > 0 || len(tail) == len(sql)) {
+		column := strings.Trim(tail[loc[2]:loc[3]], "`\"[]")
+		if idx := strings.LastIndexByte(column, '.'); idx >= 0 {
+			column = strings.Trim(column[idx+1:], "`\"[]")
+		}
+		return column
+	}
+	return ""
+}
+
+// labelsVars returns true if the logger needs vars labeled with columns, see logger.TraceEventFilter
+func (stmt *Statement) labelsVars() bool {
+	if filter, ok := stmt.DB.Logger.(logger.TraceEventFilter); ok {
+		return filter.LabelVars()
+	}
+	_, ok := stmt.DB.Logger.(logger.EventTracer)
+	return ok
+}
+
+// labelVars label added vars with the columns before them, the labels are used to redact vars when logging
+// vars are labeled with the column compared in SQL before them if found, e.g: raw conditions `password = ?`,
+// or positionally if there are same number of vars and quoted columns, e.g: VALUES, otherwise the column is unknown
+func (stmt *Statement) labelVars(idx, count, sqlLen int) {
+	var column string
+	if sqlLen >= 0 {
+		if column = rawVarColumn(stmt.SQL.String()[:sqlLen]); column == "" && len(stmt.quotedColumns) == count {
+			column = stmt.quotedColumns[idx]
+		}
+	}
+
+	for len(stmt.varColumns) < len(stmt.Vars) {
+		stmt.varColumns = append(stmt.varColumns, column)
+	}
+}
+
+// VarColumns returns columns of statement's vars, column is blank if it is unknown, e.g: vars of raw SQL not compared with columns
+func (stmt *Statement) VarColumns() []string {
+	columns := make([]string, len(stmt.Vars))
+	copy(columns, stmt.varColumns)
+	return columns
+}
+
 // AddClause add clause
 func (stmt *Statement) AddClause(v clause.Interface) {
 	if optimizer, ok := v.(StatementModifier); ok {
@@ -333,6 +465,7 @@ func (stmt *Statement) Parse(value interface{}) (err error) {
 func (stmt *Statement) clone() *Statement {
 	newStmt := &Statement{
 		Table:                stmt.Table,
+		TableExpr:            stmt.TableExpr,
 		Model:                stmt.Model,
 		Dest:                 stmt.Dest,
 		ReflectValue:         stmt.ReflectValue,
@@ -346,6 +479,8 @@ func (stmt *Statement) clone() *Statement {
 		Schema:               stmt.Schema,
 		Context:              stmt.Context,
 		RaiseErrorOnNotFound: stmt.RaiseErrorOnNotFound,
+		identifiers:          stmt.identifiers[:len(stmt.identifiers):len(stmt.identifiers)],
+		exprJoins:            stmt.exprJoins[:len(stmt.exprJoins):len(stmt.exprJoins)],
 	}
 
 	for k, c := range stmt.Clauses {
@@ -367,3 +502,65 @@ func (stmt *Statement) clone() *Statement {
 
 	return newStmt
 }
+
+// validateIdentifiers validate identifiers from Select, Order, Group, Joins string arguments with statement's schema,
+// clause.Expr arguments are trusted
+func (stmt *Statement) validateIdentifiers() error {
+	for _, name := range stmt.Selects {
+		if name == "*" || name == clause.Associations || (stmt.Schema != nil && stmt.Schema.LookUpField(name) != nil) {
+			continue
+		}
+
+		if results := columnRegexp.FindStringSubmatch(name); len(results) != 3 || !stmt.isKnownColumn(clause.Column{Table: results[1], Name: results[2]}) {
+			return fmt.Errorf("%w: %v", ErrInvalidIdentifier, name)
+		}
+	}
+
+JOINS:
+	for name := range stmt.Joins {
+		for _, expr := range stmt.exprJoins {
+			if expr == name {
+				continue JOINS
+			}
+		}
+
+		if stmt.Schema == nil {
+			return fmt.Errorf("%w: %v", ErrInvalidIdentifier, name)
+		} else if _, ok := stmt.Schema.Relationships.Relations[name]; !ok {
+			return fmt.Errorf("%w: %v", ErrInvalidIdentifier, name)
+		}
+	}
+
+	for _, column := range stmt.identifiers {
+		if !stmt.isKnownColumn(column) {
+			if column.Table != "" {
+				return fmt.Errorf("%w: %v.%v", ErrInvalidIdentifier, column.Table, column.Name)
+			}
+			return fmt.Errorf("%w: %v", ErrInvalidIdentifier, column.Name)
+		}
+	}
+	return nil
+}
+
+// isKnownColumn returns true if column is a column of current table or joined associations
+func (stmt *Statement) isKnownColumn(column clause.Column) bool {
+	if stmt.Schema == nil {
+		return false
+	}
+
+	s := stmt.Schema
+	if column.Table != "" && column.Table != stmt.Table {
+		if _, ok := stmt.Joins[column.Table]; !ok {
+			return false
+		}
+
+		relation, ok := s.Relationships.Relations[column.Table]
+		if !ok {
+			return false
+		}
+		s = relation.FieldSchema
+	}
+
+	_, ok := s.FieldsByDBName[column.Name]
+	return ok
+}
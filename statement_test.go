@@ -34,3 +34,23 @@ func TestWhereCloneCorruption(t *testing.T) {
 		})
 	}
 }
+
+func TestRawVarColumn(t *testing.T) {
+	for sql, column := range map[string]string{
+		"password = ":                             "password",
+		"SELECT * FROM users WHERE name LIKE ":    "name",
+		"`users`.`age`>=":                         "age",
+		`"users"."id" IN (`:                       "id",
+		`"users"."id" IN ($1, $2,`:                "id",
+		"[users].[code] <> ":                      "code",
+		"name = ? AND email = ":                   "email",
+		"INSERT INTO users (name,age) VALUES (":   "",
+		"INSERT INTO users (name,age) VALUES (?,": "",
+		"SELECT ":        "",
+		"LOWER(name) = ": "",
+	} {
+		if result := rawVarColumn(sql); result != column {
+			t.Errorf("column of var after %q should be %q, but got %q", sql, column, result)
+		}
+	}
+}
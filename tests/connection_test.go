@@ -0,0 +1,67 @@
+package tests_test
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestConnection(t *testing.T) {
+	if DB.Dialector.Name() == "sqlserver" {
+		t.Skip("sqlserver uses # prefixed temporary tables")
+	}
+
+	var count int64
+	if err := DB.Connection(func(tx *gorm.DB) error {
+		if err := tx.Exec("CREATE TEMPORARY TABLE connection_temps (id INT)").Error; err != nil {
+			return err
+		}
+		defer tx.Exec("DROP TABLE connection_temps")
+
+		if err := tx.Exec("INSERT INTO connection_temps (id) VALUES (1)").Error; err != nil {
+			return err
+		}
+
+		return tx.Transaction(func(tx2 *gorm.DB) error {
+			return tx2.Table("connection_temps").Count(&count).Error
+		})
+	}); err != nil {
+		t.Fatalf("temporary table should be accessible with the pinned connection, got error %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("should find record in temporary table, got %v", count)
+	}
+}
+
+func TestConnectionWithPreparedStmt(t *testing.T) {
+	db, err := OpenTestConnection()
+	if err != nil {
+		t.Fatalf("failed to connect database, got error %v", err)
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	tx := db.Session(&gorm.Session{PrepareStmt: true})
+
+	var user User
+	if err := tx.Connection(func(tx *gorm.DB) error {
+		if _, ok := tx.Statement.ConnPool.(*gorm.PreparedStmtDB); !ok {
+			t.Errorf("pinned connection should be wrapped with PreparedStmtDB, got %T", tx.Statement.ConnPool)
+		}
+
+		if err := tx.Create(GetUser("connection-prepared", Config{})).Error; err != nil {
+			return err
+		}
+		return tx.First(&user, "name = ?", "connection-prepared").Error
+	}); err != nil {
+		t.Fatalf("no error should happen, but got %v", err)
+	}
+
+	if _, ok := db.Statement.ConnPool.(*gorm.PreparedStmtDB); ok {
+		t.Errorf("PrepareStmt session should not change the conn pool of the parent db")
+	}
+}
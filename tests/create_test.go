@@ -1,6 +1,7 @@
 package tests_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -352,3 +353,142 @@ func TestOmitWithCreate(t *testing.T) {
 
 	CheckUser(t, result2, user2)
 }
+
+type placeholderLimitedDialector struct {
+	gorm.Dialector
+	maxPlaceholders int
+}
+
+func (d placeholderLimitedDialector) MaxPlaceholders() int {
+	return d.maxPlaceholders
+}
+
+func TestCreateInBatches(t *testing.T) {
+	users := []User{
+		*GetUser("create_in_batches_1", Config{Account: true, Pets: 2, Toys: 3, Company: true, Manager: true, Team: 0, Languages: 1, Friends: 1}),
+		*GetUser("create_in_batches_2", Config{Account: false, Pets: 2, Toys: 4, Company: false, Manager: false, Team: 1, Languages: 3, Friends: 5}),
+		*GetUser("create_in_batches_3", Config{Account: true, Pets: 0, Toys: 3, Company: true, Manager: false, Team: 4, Languages: 0, Friends: 1}),
+		*GetUser("create_in_batches_4", Config{Account: true, Pets: 3, Toys: 0, Company: false, Manager: true, Team: 0, Languages: 3, Friends: 0}),
+		*GetUser("create_in_batches_5", Config{Account: false, Pets: 0, Toys: 3, Company: true, Manager: false, Team: 1, Languages: 3, Friends: 1}),
+	}
+
+	if results := DB.CreateInBatches(&users, 2); results.Error != nil {
+		t.Fatalf("errors happened when create in batches: %v", results.Error)
+	} else if results.RowsAffected != int64(len(users)) {
+		t.Fatalf("rows affected expects: %v, got %v", len(users), results.RowsAffected)
+	}
+
+	var userIDs []uint
+	for _, user := range users {
+		if user.ID == 0 {
+			t.Fatalf("primary key should be backfilled, got %+v", user)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	var users2 []User
+	DB.Preload("Account").Preload("Pets").Preload("Toys").Preload("Company").Preload("Manager").Preload("Team").Preload("Languages").Preload("Friends").Order("id").Find(&users2, "id IN ?", userIDs)
+	for idx, user := range users2 {
+		CheckUser(t, user, users[idx])
+	}
+}
+
+func TestCreateInBatchesWithPlaceholderLimit(t *testing.T) {
+	tx := DB.Session(&gorm.Session{})
+	tx.Dialector = placeholderLimitedDialector{Dialector: DB.Dialector, maxPlaceholders: 50}
+	tx.CreateBatchSize = 100
+
+	var users []User
+	for i := 0; i < 10; i++ {
+		users = append(users, *GetUser(fmt.Sprintf("create_in_batches_limit_%v", i), Config{}))
+	}
+
+	var statements int
+	tx.Callback().Create().Before("gorm:create").Register("create_in_batches_limit:count", func(db *gorm.DB) {
+		if db.Statement.Schema != nil && db.Statement.Schema.Table == "users" {
+			statements++
+		}
+	})
+	defer tx.Callback().Create().Remove("create_in_batches_limit:count")
+
+	if err := tx.Create(&users).Error; err != nil {
+		t.Fatalf("errors happened when create: %v", err)
+	}
+
+	if statements <= 1 {
+		t.Errorf("users should be created in batches capped by max placeholders, got %v statements", statements)
+	}
+
+	var count int64
+	if DB.Model(&User{}).Where("name LIKE ?", "create_in_batches_limit_%").Count(&count); count != 10 {
+		t.Errorf("all users should be created, got %v", count)
+	}
+}
+
+type DefaultValueRefreshComposite struct {
+	TenantID   uint       `gorm:"primary_key"`
+	Code       string     `gorm:"primary_key"`
+	RecordedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+type DefaultValueRefresh struct {
+	ID         uint
+	Name       string
+	RecordedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func TestCreateWithRefreshDefaultValues(t *testing.T) {
+	DB.Migrator().DropTable(&DefaultValueRefresh{})
+	if err := DB.AutoMigrate(&DefaultValueRefresh{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	tx := DB.Session(&gorm.Session{RefreshDefaultValues: true})
+
+	record := DefaultValueRefresh{Name: "refresh"}
+	if err := tx.Create(&record).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	if record.ID == 0 || record.RecordedAt == nil {
+		t.Errorf("database default values should be refreshed after create, got %+v", record)
+	}
+
+	records := []DefaultValueRefresh{{Name: "refresh-1"}, {Name: "refresh-2"}, {Name: "refresh-3"}}
+	if err := tx.CreateInBatches(&records, 2).Error; err != nil {
+		t.Fatalf("failed to create in batches, got error %v", err)
+	}
+
+	for _, record := range records {
+		if record.ID == 0 || record.RecordedAt == nil {
+			t.Errorf("database default values should be refreshed after batch create, got %+v", record)
+		}
+	}
+
+	DB.Migrator().DropTable(&DefaultValueRefreshComposite{})
+	if err := DB.AutoMigrate(&DefaultValueRefreshComposite{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	composites := []DefaultValueRefreshComposite{{TenantID: 1, Code: "refresh"}, {TenantID: 2, Code: "refresh"}}
+	if err := tx.Create(&composites).Error; err != nil {
+		t.Fatalf("failed to create records with composite primary keys, got error %v", err)
+	}
+
+	for _, composite := range composites {
+		if composite.RecordedAt == nil {
+			t.Errorf("database default values should be refreshed for composite primary keys, got %+v", composite)
+		}
+	}
+}
+
+func TestCreateFromMap(t *testing.T) {
+	if err := DB.Model(&User{}).Create(map[string]interface{}{"Name": "create_from_map", "Age": 18}).Error; err != nil {
+		t.Fatalf("failed to create from map, got error %v", err)
+	}
+
+	var ages []int
+	if err := DB.Model(&User{}).Where("name = ?", "create_from_map").Pluck("age", &ages).Error; err != nil || len(ages) != 1 || ages[0] != 18 {
+		t.Errorf("failed to query created user, got %+v, %v", ages, err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -68,3 +69,173 @@ func TestBlockGlobalDelete(t *testing.T) {
 		t.Errorf("should returns missing WHERE clause while deleting error")
 	}
 }
+
+func TestDeleteWithAssociations(t *testing.T) {
+	user := GetUser("delete_with_associations", Config{Account: true, Pets: 2, Toys: 2, Languages: 2})
+	if err := DB.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	if err := DB.Select("Account", "Pets", "Toys", "Languages").Delete(user).Error; err != nil {
+		t.Fatalf("failed to delete user, got error %v", err)
+	}
+
+	counts := func(unscoped bool) (accounts, pets, toys int64) {
+		tx := func() *gorm.DB {
+			if unscoped {
+				return DB.Unscoped()
+			}
+			return DB
+		}
+
+		tx().Model(&Account{}).Where("user_id = ?", user.ID).Count(&accounts)
+		tx().Model(&Pet{}).Where("user_id = ?", user.ID).Count(&pets)
+		tx().Model(&Toy{}).Where("owner_id = ? AND owner_type = ?", user.ID, "users").Count(&toys)
+		return
+	}
+
+	if accounts, pets, toys := counts(false); accounts != 0 || pets != 0 || toys != 0 {
+		t.Errorf("associations should be soft deleted, got %v, %v, %v", accounts, pets, toys)
+	}
+
+	if accounts, pets, toys := counts(true); accounts != 1 || pets != 2 || toys != 2 {
+		t.Errorf("associations should be soft deleted instead of permanently deleted, got %v, %v, %v", accounts, pets, toys)
+	}
+
+	if count := DB.Unscoped().Model(user).Association("Languages").Count(); count != 0 {
+		t.Errorf("join table records should be deleted, got %v", count)
+	}
+
+	if err := DB.Select("Account", "Pets", "Toys").Restore(user).Error; err != nil {
+		t.Fatalf("failed to restore user, got error %v", err)
+	}
+
+	if accounts, pets, toys := counts(false); accounts != 1 || pets != 2 || toys != 2 {
+		t.Errorf("associations should be restored, got %v, %v, %v", accounts, pets, toys)
+	}
+
+	if err := DB.Select("Account", "Pets", "Toys").Unscoped().Delete(user).Error; err != nil {
+		t.Fatalf("failed to delete user permanently, got error %v", err)
+	}
+
+	if accounts, pets, toys := counts(true); accounts != 0 || pets != 0 || toys != 0 {
+		t.Errorf("associations should be permanently deleted, got %v, %v, %v", accounts, pets, toys)
+	}
+}
+
+type CascadeOrder struct {
+	ID             uint
+	CascadeBuyerID uint
+	Name           string
+	DeletedAt      gorm.DeletedAt
+}
+
+type CascadeInvoice struct {
+	ID             uint
+	CascadeBuyerID uint
+	Name           string
+}
+
+type CascadeAddress struct {
+	ID             uint
+	CascadeBuyerID uint
+	Name           string
+	DeletedAt      gorm.DeletedAt
+}
+
+type CascadeBuyer struct {
+	ID        uint
+	Name      string
+	Orders    []CascadeOrder   `gorm:"cascadeDelete"`
+	Invoices  []CascadeInvoice `gorm:"cascadeDelete;constraint:OnDelete:CASCADE"`
+	Addresses []CascadeAddress `gorm:"constraint:OnDelete:CASCADE"`
+	DeletedAt gorm.DeletedAt
+}
+
+func TestDeleteWithCascadeTag(t *testing.T) {
+	DB.Migrator().DropTable(&CascadeOrder{}, &CascadeInvoice{}, &CascadeAddress{}, &CascadeBuyer{})
+	if err := DB.AutoMigrate(&CascadeBuyer{}, &CascadeOrder{}, &CascadeInvoice{}, &CascadeAddress{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	buyer := CascadeBuyer{
+		Name:      "cascade",
+		Orders:    []CascadeOrder{{Name: "order_1"}, {Name: "order_2"}},
+		Invoices:  []CascadeInvoice{{Name: "invoice"}},
+		Addresses: []CascadeAddress{{Name: "address"}},
+	}
+	DB.Create(&buyer)
+
+	if err := DB.Delete(&buyer).Error; err != nil {
+		t.Fatalf("failed to delete, got error %v", err)
+	}
+
+	var count int64
+	if DB.Model(&CascadeOrder{}).Where("cascade_buyer_id = ?", buyer.ID).Count(&count); count != 0 {
+		t.Errorf("orders should be soft deleted with cascadeDelete tag, got %v", count)
+	}
+
+	if DB.Model(&CascadeInvoice{}).Where("cascade_buyer_id = ?", buyer.ID).Count(&count); count != 1 {
+		t.Errorf("invoices can't be soft deleted should not be deleted when soft deleting, got %v", count)
+	}
+
+	if DB.Model(&CascadeAddress{}).Where("cascade_buyer_id = ?", buyer.ID).Count(&count); count != 1 {
+		t.Errorf("addresses without cascadeDelete tag should not be deleted, got %v", count)
+	}
+
+	if err := DB.Restore(&buyer).Error; err != nil {
+		t.Fatalf("failed to restore, got error %v", err)
+	}
+
+	if DB.Model(&CascadeOrder{}).Where("cascade_buyer_id = ?", buyer.ID).Count(&count); count != 2 {
+		t.Errorf("orders should be restored with cascadeDelete tag, got %v", count)
+	}
+
+	if err := DB.Omit("Orders").Delete(&buyer).Error; err != nil {
+		t.Fatalf("failed to delete, got error %v", err)
+	}
+
+	if DB.Model(&CascadeOrder{}).Where("cascade_buyer_id = ?", buyer.ID).Count(&count); count != 2 {
+		t.Errorf("omitted orders should not be deleted, got %v", count)
+	}
+
+	if err := DB.Unscoped().Delete(&buyer).Error; err != nil {
+		t.Fatalf("failed to delete permanently, got error %v", err)
+	}
+
+	if DB.Model(&CascadeInvoice{}).Where("cascade_buyer_id = ?", buyer.ID).Count(&count); count != 0 {
+		t.Errorf("invoices should be deleted when deleting permanently, got %v", count)
+	}
+}
+
+func TestDeleteReturning(t *testing.T) {
+	if DB.Dialector.Name() != "postgres" {
+		t.Skip()
+	}
+
+	users := []User{*GetUser("delete-returning", Config{}), *GetUser("delete-returning", Config{})}
+	DB.Create(&users)
+
+	var deleted []User
+	if err := DB.Clauses(clause.Returning{}).Where("name = ?", "delete-returning").Delete(&deleted).Error; err != nil {
+		t.Fatalf("failed to delete with returning, got error %v", err)
+	}
+
+	if len(deleted) != 2 {
+		t.Fatalf("deleted rows should be returned, got %v", len(deleted))
+	}
+
+	for _, user := range deleted {
+		if user.Name != "delete-returning" || !user.DeletedAt.Valid {
+			t.Errorf("soft deleted values should be scanned into the model, got %+v", user)
+		}
+	}
+
+	user := *GetUser("delete-returning-unscoped", Config{})
+	DB.Create(&user)
+
+	var result User
+	if err := DB.Unscoped().Clauses(clause.Returning{}).Where("id = ?", user.ID).Delete(&result).Error; err != nil || result.Name != user.Name {
+		t.Errorf("deleted row should be returned, got %+v, %v", result, err)
+	}
+}
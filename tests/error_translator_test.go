@@ -0,0 +1,50 @@
+package tests_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type translatorDialector struct {
+	gorm.Dialector
+	driverErrs []error
+}
+
+func (d *translatorDialector) Translate(err error) error {
+	d.driverErrs = append(d.driverErrs, err)
+	if msg := strings.ToLower(err.Error()); strings.Contains(msg, "unique") || strings.Contains(msg, "duplicate") {
+		return gorm.ErrDuplicatedKey
+	}
+	return err
+}
+
+func TestErrorTranslator(t *testing.T) {
+	dialector := &translatorDialector{Dialector: DB.Dialector}
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	user := *GetUser("error_translator", Config{})
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	err = db.Create(&User{Model: gorm.Model{ID: user.ID}, Name: "error_translator_duplicated"}).Error
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("should returns ErrDuplicatedKey, got %v", err)
+	}
+
+	var translatedErr *gorm.TranslatedError
+	if !errors.As(err, &translatedErr) || translatedErr.DriverErr != dialector.driverErrs[len(dialector.driverErrs)-1] || !errors.Is(err, translatedErr.DriverErr) {
+		t.Errorf("driver error should be reachable, got %v", err)
+	}
+
+	if err := db.First(&User{}, "name = ?", "error_translator_non_existing").Error; !errors.Is(err, gorm.ErrRecordNotFound) || errors.As(err, &translatedErr) {
+		t.Errorf("unknown errors should not be translated, got %v", err)
+	}
+}
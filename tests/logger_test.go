@@ -0,0 +1,132 @@
+package tests_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type eventLogger struct {
+	logger.Interface
+	events []logger.TraceEvent
+}
+
+func (l *eventLogger) TraceEvent(ctx context.Context, event logger.TraceEvent) {
+	l.events = append(l.events, event)
+}
+
+type filteredEventLogger struct {
+	eventLogger
+	enabled bool
+}
+
+func (l *filteredEventLogger) LabelVars() bool {
+	return l.enabled
+}
+
+func (l *filteredEventLogger) TraceEventEnabled(time.Duration, error) bool {
+	return l.enabled
+}
+
+type bufferWriter struct {
+	bytes.Buffer
+}
+
+func (w *bufferWriter) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(&w.Buffer, format, args...)
+}
+
+func TestTraceEvent(t *testing.T) {
+	l := &eventLogger{Interface: logger.Default}
+	tx := DB.Session(&gorm.Session{Logger: l})
+
+	user := *GetUser("trace_event", Config{})
+	if err := tx.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	if len(l.events) == 0 {
+		t.Fatalf("should receive trace events")
+	}
+
+	event := l.events[len(l.events)-1]
+	if event.Operation != "create" || event.Table != "users" || !strings.Contains(event.SQL, "INSERT INTO") || event.Rows != 1 || event.Error != nil {
+		t.Errorf("invalid create trace event, got %+v", event)
+	}
+
+	if !strings.Contains(event.Caller, "logger_test.go") {
+		t.Errorf("caller should be the test file, got %v", event.Caller)
+	}
+
+	if len(event.VarColumns) != len(event.Vars) {
+		t.Fatalf("var columns should have same length with vars, got %v, %v", event.VarColumns, event.Vars)
+	}
+
+	for idx, column := range event.VarColumns {
+		if column == "name" && event.Vars[idx] != user.Name {
+			t.Errorf("var of column name should be user's name, got %v", event.Vars[idx])
+		}
+	}
+
+	l.events = nil
+	var result User
+	if err := tx.Where("name = ?", user.Name).First(&result).Error; err != nil {
+		t.Fatalf("failed to query user, got error %v", err)
+	}
+
+	if event := l.events[len(l.events)-1]; event.Operation != "query" || event.Table != "users" || len(event.Vars) != 1 || event.Vars[0] != user.Name || !strings.Contains(event.Explain(), user.Name) {
+		t.Errorf("invalid query trace event, got %+v", event)
+	}
+
+	writer := &bufferWriter{}
+	redactLogger := logger.New(writer, logger.Config{LogLevel: logger.Info, Redactor: func(column string, value interface{}) interface{} {
+		if column == "name" {
+			return "***"
+		}
+		return value
+	}})
+
+	if err := DB.Session(&gorm.Session{Logger: redactLogger}).Model(&result).Update("name", "trace_event_secret").Error; err != nil {
+		t.Fatalf("failed to update user, got error %v", err)
+	}
+
+	if output := writer.String(); strings.Contains(output, "trace_event_secret") || !strings.Contains(output, "***") || !strings.Contains(output, "logger_test.go") {
+		t.Errorf("name should be redacted in logs, got %v", output)
+	}
+
+	writer.Reset()
+	DB.Session(&gorm.Session{Logger: redactLogger}).Where("name = ? AND age > ?", "trace_event_secret", 0).Find(&[]User{})
+	if output := writer.String(); strings.Contains(output, "trace_event_secret") || !strings.Contains(output, "***") {
+		t.Errorf("name of raw conditions should be redacted in logs, got %v", output)
+	}
+
+	writer.Reset()
+	DB.Session(&gorm.Session{Logger: redactLogger}).Where("name = ? AND age + ? > 0", "trace_event_secret", 18).Find(&[]User{})
+	if output := writer.String(); !strings.Contains(output, logger.RedactedValue) {
+		t.Errorf("vars of unknown columns should be redacted in logs, got %v", output)
+	}
+}
+
+func TestTraceEventFilter(t *testing.T) {
+	l := &filteredEventLogger{eventLogger: eventLogger{Interface: logger.Default}}
+	tx := DB.Session(&gorm.Session{Logger: l})
+
+	var result User
+	tx.Where("name = ?", "trace_event_filter").Find(&result)
+	if len(l.events) != 0 {
+		t.Errorf("should not receive disabled trace events, got %+v", l.events)
+	}
+
+	l.enabled = true
+	tx.Where("name = ?", "trace_event_filter").Find(&result)
+	if len(l.events) != 1 || len(l.events[0].VarColumns) != 1 || l.events[0].VarColumns[0] != "name" {
+		t.Errorf("should receive enabled trace events with labeled vars, got %+v", l.events)
+	}
+}
@@ -1,6 +1,7 @@
 package tests_test
 
 import (
+	"errors"
 	"math/rand"
 	"strings"
 	"testing"
@@ -241,3 +242,201 @@ func TestColumns(t *testing.T) {
 		t.Fatalf("Found deleted column")
 	}
 }
+
+func TestMigratePlanIdempotent(t *testing.T) {
+	type IdempotentPlanStruct struct {
+		gorm.Model
+		Name     string `gorm:"size:100;not null"`
+		Code     string `gorm:"type:varchar(20);uniqueIndex"`
+		Active   bool
+		Enabled  *bool `gorm:"default:true"`
+		Level    int8
+		Count    uint32
+		Score    float64
+		Amount   float64 `gorm:"precision:10;scale:2"`
+		Birthday *time.Time
+		Data     []byte
+		Note     string
+		Status   string `gorm:"size:20;default:active"`
+		Rank     uint   `gorm:"not null;default:1"`
+	}
+
+	DB.Migrator().DropTable(&IdempotentPlanStruct{})
+	for i := 0; i < 2; i++ {
+		if err := DB.AutoMigrate(&IdempotentPlanStruct{}); err != nil {
+			t.Fatalf("failed to migrate, got error %v", err)
+		}
+	}
+
+	if plan, err := DB.Migrator().Plan(&IdempotentPlanStruct{}); err != nil || len(plan.Changes) != 0 {
+		t.Errorf("migrated model should have no changes, got %+v, error %v", plan.Changes, err)
+	}
+}
+
+func TestMigratePlan(t *testing.T) {
+	type PlanStruct struct {
+		gorm.Model
+		Name string
+	}
+
+	type NewPlanStruct struct {
+		gorm.Model
+		Name string `gorm:"type:varchar(100)"`
+		Age  uint   `gorm:"index:idx_plan_structs_age"`
+	}
+
+	DB.Migrator().DropTable("plan_structs")
+
+	plan, err := DB.Table("plan_structs").Migrator().Plan(&PlanStruct{})
+	if err != nil {
+		t.Fatalf("failed to plan, got error %v", err)
+	}
+
+	if len(plan.Changes) != 1 || plan.Changes[0].Type != gorm.CreateTableChange || len(plan.SQL()) == 0 || !strings.Contains(plan.SQL()[0], "CREATE TABLE") {
+		t.Fatalf("should plan to create table, got %+v", plan.Changes)
+	}
+
+	if DB.Migrator().HasTable("plan_structs") {
+		t.Fatalf("plan should not create table")
+	}
+
+	if err := DB.Table("plan_structs").AutoMigrate(&PlanStruct{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if plan, err = DB.Table("plan_structs").Migrator().Plan(&PlanStruct{}); err != nil || len(plan.Changes) != 0 {
+		t.Fatalf("should have no changes after migrated, got %+v, error %v", plan.Changes, err)
+	}
+
+	if plan, err = DB.Table("plan_structs").Migrator().Plan(&NewPlanStruct{}); err != nil {
+		t.Fatalf("failed to plan, got error %v", err)
+	}
+
+	changes := map[string]gorm.SchemaChange{}
+	for _, change := range plan.Changes {
+		changes[string(change.Type)+":"+change.Name] = change
+	}
+
+	if change, ok := changes["add_column:age"]; !ok || len(change.SQL) == 0 {
+		t.Errorf("should plan to add column age, got %+v", plan.Changes)
+	}
+
+	if change, ok := changes["alter_column:name"]; !ok || len(change.Details) == 0 || len(change.SQL) == 0 {
+		t.Errorf("should plan to alter column name, got %+v", plan.Changes)
+	}
+
+	if change, ok := changes["create_index:idx_plan_structs_age"]; !ok || len(change.SQL) == 0 {
+		t.Errorf("should plan to create index, got %+v", plan.Changes)
+	}
+
+	if DB.Table("plan_structs").Migrator().HasColumn(&NewPlanStruct{}, "age") {
+		t.Errorf("plan should not add column")
+	}
+}
+
+func TestMigratePlanColumnAttributes(t *testing.T) {
+	type AttributePlanStruct struct {
+		gorm.Model
+		Name string
+	}
+
+	type NewAttributePlanStruct struct {
+		gorm.Model
+		Name string `gorm:"not null;default:hello"`
+	}
+
+	DB.Migrator().DropTable("attribute_plan_structs")
+	if err := DB.Table("attribute_plan_structs").AutoMigrate(&AttributePlanStruct{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	plan, err := DB.Table("attribute_plan_structs").Migrator().Plan(&NewAttributePlanStruct{})
+	if err != nil {
+		t.Fatalf("failed to plan, got error %v", err)
+	}
+
+	var details []string
+	for _, change := range plan.Changes {
+		if change.Type == gorm.AlterColumnChange && change.Name == "name" {
+			details = change.Details
+		}
+	}
+
+	if details := strings.Join(details, ", "); !strings.Contains(details, "default") || !strings.Contains(details, "nullable") {
+		t.Errorf("should plan to change default value and nullability of column name, got %+v", plan.Changes)
+	}
+}
+
+func TestAutoMigrateAlterColumns(t *testing.T) {
+	type AlterStruct struct {
+		gorm.Model
+		Name string
+		Age  int32
+	}
+
+	type NewAlterStruct struct {
+		gorm.Model
+		Name string `gorm:"type:varchar(100)"`
+		Age  int64  `gorm:"type:bigint"`
+	}
+
+	type NoAlterStruct struct {
+		gorm.Model
+		Name string `gorm:"type:varchar(100);noalter"`
+		Age  int64  `gorm:"type:bigint"`
+	}
+
+	columnType := func(name string) string {
+		columnTypes, _ := DB.Migrator().ColumnTypes("alter_structs")
+		for _, columnType := range columnTypes {
+			if columnType.Name() == name {
+				return strings.ToLower(columnType.DatabaseTypeName())
+			}
+		}
+		return ""
+	}
+
+	DB.Migrator().DropTable(&AlterStruct{})
+	if err := DB.AutoMigrate(&AlterStruct{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+	DB.Create(&AlterStruct{Name: "alter", Age: 18})
+
+	if err := DB.Table("alter_structs").AutoMigrate(&NewAlterStruct{}); !errors.Is(err, gorm.ErrDestructiveMigration) {
+		t.Fatalf("should refuse to narrow column, got error %v", err)
+	}
+
+	if columnType("age") == "bigint" {
+		t.Errorf("column age should not be altered when migration refused")
+	}
+
+	if DB.Dialector.Name() == "sqlite" {
+		t.Skip("skip sqlite due to its driver can't alter column")
+	}
+
+	if err := DB.Table("alter_structs").AutoMigrate(&NoAlterStruct{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if columnType("age") != "bigint" || columnType("name") == "varchar(100)" {
+		t.Errorf("only column age should be altered, got age: %v, name: %v", columnType("age"), columnType("name"))
+	}
+
+	db, err := gorm.Open(DB.Dialector, &gorm.Config{AllowDestructiveMigration: true})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	if err := db.Table("alter_structs").AutoMigrate(&NewAlterStruct{}); err != nil {
+		t.Fatalf("failed to migrate when destructive migration allowed, got error %v", err)
+	}
+
+	if plan, err := DB.Table("alter_structs").Migrator().Plan(&NewAlterStruct{}); err != nil || len(plan.Changes) != 0 {
+		t.Errorf("should have no changes after columns altered, got %+v, error %v", plan.Changes, err)
+	}
+
+	var result NewAlterStruct
+	if err := DB.Table("alter_structs").First(&result, "name = ?", "alter").Error; err != nil || result.Age != 18 {
+		t.Errorf("data should be kept after columns altered, got %+v, error %v", result, err)
+	}
+}
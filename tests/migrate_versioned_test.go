@@ -0,0 +1,170 @@
+package tests_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrate"
+)
+
+func TestVersionedMigrate(t *testing.T) {
+	type MigrateProduct struct {
+		ID   uint
+		Name string
+	}
+
+	type MigrateOrder struct {
+		ID        uint
+		ProductID uint
+	}
+
+	DB.Migrator().DropTable(&MigrateProduct{}, &MigrateOrder{}, "versioned_migrations", "versioned_migrations_lock")
+
+	migrations := []*migrate.Migration{{
+		ID: "202010010002",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&MigrateOrder{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&MigrateOrder{})
+		},
+	}, {
+		ID: "202010010001",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&MigrateProduct{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&MigrateProduct{})
+		},
+	}, {
+		ID: "202010010003",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.Create(&MigrateProduct{Name: "migrate"}).Error
+		},
+	}}
+
+	m := migrate.New(DB, &migrate.Options{TableName: "versioned_migrations"}, migrations)
+
+	if err := m.MigrateTo("202010010001"); err != nil {
+		t.Fatalf("failed to migrate to 202010010001, got error %v", err)
+	}
+
+	if !DB.Migrator().HasTable(&MigrateProduct{}) || DB.Migrator().HasTable(&MigrateOrder{}) {
+		t.Errorf("should only run migrations until 202010010001")
+	}
+
+	if err := m.Migrate(); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if applied, err := m.Applied(); err != nil || !reflect.DeepEqual(applied, []string{"202010010001", "202010010002", "202010010003"}) {
+		t.Errorf("all migrations should be applied, got %v, error %v", applied, err)
+	}
+
+	if err := m.Migrate(); err != nil {
+		t.Fatalf("failed to migrate again, got error %v", err)
+	}
+
+	var count int64
+	if DB.Model(&MigrateProduct{}).Count(&count); count != 1 {
+		t.Errorf("applied migrations should not run again, but got %v products", count)
+	}
+
+	if err := m.RollbackLast(); !errors.Is(err, migrate.ErrRollbackNotDefined) {
+		t.Errorf("should returns ErrRollbackNotDefined, but got %v", err)
+	}
+
+	if err := m.MigrateTo("unknown"); !errors.Is(err, migrate.ErrUnknownMigration) {
+		t.Errorf("should returns ErrUnknownMigration, but got %v", err)
+	}
+
+	migrations[2].Rollback = func(tx *gorm.DB) error {
+		return tx.Where("name = ?", "migrate").Delete(&MigrateProduct{}).Error
+	}
+
+	if err := m.RollbackLast(); err != nil {
+		t.Fatalf("failed to rollback last, got error %v", err)
+	}
+
+	if DB.Model(&MigrateProduct{}).Count(&count); count != 0 {
+		t.Errorf("last migration should be rollbacked, but got %v products", count)
+	}
+
+	if err := m.RollbackTo("202010010001"); err != nil {
+		t.Fatalf("failed to rollback to 202010010001, got error %v", err)
+	}
+
+	if !DB.Migrator().HasTable(&MigrateProduct{}) || DB.Migrator().HasTable(&MigrateOrder{}) {
+		t.Errorf("should rollback migrations after 202010010001")
+	}
+
+	if applied, err := m.Applied(); err != nil || !reflect.DeepEqual(applied, []string{"202010010001"}) {
+		t.Errorf("only 202010010001 should be applied, got %v, error %v", applied, err)
+	}
+
+	if err := migrate.New(DB, &migrate.Options{TableName: "versioned_migrations"}, append(migrations, &migrate.Migration{ID: "202010010001"})).Migrate(); !errors.Is(err, migrate.ErrDuplicatedID) {
+		t.Errorf("should returns ErrDuplicatedID, but got %v", err)
+	}
+
+	if DB.Migrator().HasTable("versioned_migrations_lock") {
+		var locks int64
+		if DB.Table("versioned_migrations_lock").Count(&locks); locks != 0 {
+			t.Errorf("migration lock should be released, but got %v locks", locks)
+		}
+	}
+}
+
+func TestVersionedMigrateLockTable(t *testing.T) {
+	if name := DB.Dialector.Name(); name == "postgres" || name == "mysql" {
+		t.Skip("advisory locks are used")
+	}
+
+	type MigrationLock struct {
+		ID       string `gorm:"primarykey;size:255"`
+		LockedAt time.Time
+	}
+
+	DB.Migrator().DropTable("lock_migrations", "lock_migrations_lock")
+	if err := DB.Table("lock_migrations_lock").AutoMigrate(&MigrationLock{}); err != nil {
+		t.Fatalf("failed to migrate lock table, got error %v", err)
+	}
+
+	lock := MigrationLock{ID: "lock_migrations", LockedAt: time.Now()}
+	DB.Table("lock_migrations_lock").Create(&lock)
+
+	options := &migrate.Options{TableName: "lock_migrations", LockTimeout: 200 * time.Millisecond, LockExpiration: time.Minute}
+	if err := migrate.New(DB, options, nil).Migrate(); !errors.Is(err, migrate.ErrAcquireLockFailed) {
+		t.Errorf("should returns ErrAcquireLockFailed when the lock is held, but got %v", err)
+	}
+
+	DB.Table("lock_migrations_lock").Where("id = ?", lock.ID).Update("locked_at", time.Now().Add(-time.Hour))
+	if err := migrate.New(DB, options, nil).Migrate(); err != nil {
+		t.Errorf("stale lock should be released, but got error %v", err)
+	}
+
+	var locks int64
+	if DB.Table("lock_migrations_lock").Count(&locks); locks != 0 {
+		t.Errorf("migration lock should be released, but got %v locks", locks)
+	}
+}
+
+func TestVersionedMigrateWithPreparedStmt(t *testing.T) {
+	DB.Migrator().DropTable("prepared_migrations", "prepared_migrations_lock")
+
+	var applied bool
+	migrations := []*migrate.Migration{{
+		ID: "202010010001",
+		Migrate: func(tx *gorm.DB) error {
+			applied = true
+			return nil
+		},
+	}}
+
+	tx := DB.Session(&gorm.Session{PrepareStmt: true})
+	if err := migrate.New(tx, &migrate.Options{TableName: "prepared_migrations"}, migrations).Migrate(); err != nil || !applied {
+		t.Errorf("failed to migrate with prepared statements, got %v, error %v", applied, err)
+	}
+}
@@ -0,0 +1,85 @@
+package tests_test
+
+import (
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestPreparedStmtCache(t *testing.T) {
+	tx := DB.Session(&gorm.Session{PrepareStmt: true})
+	stmtDB, ok := tx.Statement.ConnPool.(*gorm.PreparedStmtDB)
+	if !ok {
+		t.Fatalf("should use PreparedStmtDB, got %T", tx.Statement.ConnPool)
+	}
+	stmtDB.MaxSize = 2
+
+	user := *GetUser("prepared_stmt_cache", Config{})
+	tx.Create(&user)
+
+	for i := 0; i < 2; i++ {
+		tx.First(&User{}, "name = ?", user.Name)
+		tx.Find(&[]User{}, "id IN ?", []uint{user.ID, user.ID + 1})
+		tx.Find(&[]User{}, "id IN ?", []uint{user.ID, user.ID + 1, user.ID + 2})
+	}
+
+	stats := stmtDB.Stats()
+	if stats.Size != 2 {
+		t.Errorf("cached statements should be limited by MaxSize, got %v", stats.Size)
+	}
+
+	if stats.Evictions == 0 || stats.Misses == 0 {
+		t.Errorf("least recently used statements should be evicted, got %+v", stats)
+	}
+
+	hits := stats.Hits
+	tx.Find(&[]User{}, "id IN ?", []uint{user.ID, user.ID + 1, user.ID + 2})
+	if stats = stmtDB.Stats(); stats.Hits != hits+1 {
+		t.Errorf("recently used statement should be cached, got %+v", stats)
+	}
+
+	hits, misses := stats.Hits, stats.Misses
+	if err := tx.Transaction(func(tx2 *gorm.DB) error {
+		for i := 0; i < 3; i++ {
+			var results []User
+			if err := tx2.Find(&results, "id IN ?", []uint{user.ID, user.ID + 1, user.ID + 2}).Error; err != nil || len(results) != 1 {
+				t.Errorf("should find user in transaction, got %v, %v", len(results), err)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Errorf("no error should happen, but got %v", err)
+	}
+
+	if stats = stmtDB.Stats(); stats.Hits != hits+1 || stats.Misses != misses {
+		t.Errorf("transaction should use cached statement, got %+v", stats)
+	}
+}
+
+func TestPreparedStmtEvictionConcurrently(t *testing.T) {
+	tx := DB.Session(&gorm.Session{PrepareStmt: true})
+	tx.Statement.ConnPool.(*gorm.PreparedStmtDB).MaxSize = 1
+
+	user := *GetUser("prepared_stmt_concurrent", Config{})
+	tx.Create(&user)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := make([]uint, i%3+1)
+			for j := range ids {
+				ids[j] = user.ID
+			}
+
+			var count int64
+			if err := tx.Model(&User{}).Where("id IN ?", ids).Count(&count).Error; err != nil || count != 1 {
+				t.Errorf("statements in use should not be closed by evictions, got %v, %v", count, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
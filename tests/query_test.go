@@ -5,10 +5,12 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -471,6 +473,110 @@ func TestSubQueryWithRaw(t *testing.T) {
 	if count != 2 {
 		t.Errorf("Row count must be 2, instead got %d", count)
 	}
+
+	err = DB.Raw("select count(*) from (?) tmp WHERE name <> ?",
+		DB.Raw("select name from users WHERE name LIKE ? AND age > ?", "subquery_raw%", 10),
+		"subquery_raw_2",
+	).Count(&count).Error
+
+	if err != nil {
+		t.Errorf("Expected to get no errors, but got %v", err)
+	}
+
+	if count != 2 {
+		t.Errorf("Row count must be 2, instead got %d", count)
+	}
+}
+
+func TestQuoteTableExpr(t *testing.T) {
+	tx := DB.Table("(?) AS u", DB.Table("users").Select("name").Where("age > ?", 18))
+
+	var builder strings.Builder
+	tx.Statement.QuoteTo(&builder, clause.Table{Name: clause.CurrentTable})
+	if !strings.Contains(builder.String(), "SELECT") || !strings.HasSuffix(builder.String(), " AS u") {
+		t.Errorf("table expression should be built to the writer, got %v", builder.String())
+	}
+
+	if fmt.Sprint(tx.Statement.Vars) != "[18]" {
+		t.Errorf("vars of table expression should be bound to the statement, got %v", tx.Statement.Vars)
+	}
+}
+
+func TestSubQueryWithIN(t *testing.T) {
+	users := []User{
+		{Name: "subquery_in_1", Age: 10},
+		{Name: "subquery_in_2", Age: 20},
+		{Name: "subquery_in_3", Age: 30},
+	}
+	DB.Create(&users)
+
+	var results []User
+	subQuery := DB.Table("users").Select("id").Where("name LIKE ? AND age > ?", "subquery_in%", 15)
+	if err := DB.Where(clause.IN{Column: "id", Values: []interface{}{subQuery}}).Where("age < ?", 30).Find(&results).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Name != "subquery_in_2" {
+		t.Errorf("should find user subquery_in_2, but got %+v", results)
+	}
+
+	if err := DB.Not(clause.IN{Column: "id", Values: []interface{}{subQuery}}).Where("name LIKE ?", "subquery_in%").Find(&results).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Name != "subquery_in_1" {
+		t.Errorf("should find user subquery_in_1, but got %+v", results)
+	}
+}
+
+func TestSubQueryWithTable(t *testing.T) {
+	users := []User{
+		{Name: "subquery_table_1", Age: 10},
+		{Name: "subquery_table_2", Age: 20},
+		{Name: "subquery_table_3", Age: 30},
+	}
+	DB.Create(&users)
+
+	var results []User
+	if err := DB.Table("(?) AS u", DB.Model(&User{}).Where("name LIKE ?", "subquery_table%")).
+		Where("age > ?", 15).Order("age").Find(&results).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(results) != 2 || results[0].Name != "subquery_table_2" || results[1].Name != "subquery_table_3" {
+		t.Errorf("should find two users from subquery table, but got %+v", results)
+	}
+
+	var names []string
+	if err := DB.Table("(?) AS u, (?) AS p", DB.Model(&User{}).Select("name").Where("name = ?", "subquery_table_1"), DB.Model(&User{}).Select("age").Where("name = ?", "subquery_table_3")).
+		Pluck("name", &names).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if len(names) != 1 || names[0] != "subquery_table_1" {
+		t.Errorf("should find name from multiple subquery tables, but got %v", names)
+	}
+}
+
+func TestSubQueryWithSelect(t *testing.T) {
+	users := []User{
+		{Name: "subquery_select_1", Age: 10},
+		{Name: "subquery_select_2", Age: 20},
+	}
+	DB.Create(&users)
+
+	var result struct {
+		Name   string
+		AvgAge float64
+	}
+	if err := DB.Table("users").Select("name, (?) AS avg_age", DB.Model(&User{}).Select("AVG(age)").Where("name LIKE ?", "subquery_select%")).
+		Where("name = ?", "subquery_select_1").Scan(&result).Error; err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+
+	if result.Name != "subquery_select_1" || result.AvgAge != 15 {
+		t.Errorf("failed to select with subquery, got %+v", result)
+	}
 }
 
 func TestSubQueryWithHaving(t *testing.T) {
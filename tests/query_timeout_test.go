@@ -0,0 +1,61 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func slowSQL() string {
+	switch DB.Dialector.Name() {
+	case "mysql":
+		return "SELECT SLEEP(2)"
+	case "postgres":
+		return "SELECT pg_sleep(2)"
+	case "sqlserver":
+		return "WAITFOR DELAY '00:00:02'"
+	default:
+		return "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM c WHERE x < 500000000) SELECT count(*) FROM c"
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	tx := DB.Session(&gorm.Session{QueryTimeout: 100 * time.Millisecond})
+
+	// rows are read after returned, they should not be canceled by the timeout
+	rows, err := tx.Raw("SELECT 1").Rows()
+	if err != nil {
+		t.Fatalf("no error should happen, but got %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	for rows.Next() {
+	}
+	if err := rows.Err(); err != nil {
+		t.Errorf("rows should not be canceled by query timeout, but got %v", err)
+	}
+	rows.Close()
+
+	if err := tx.Exec(slowSQL()).Error; !errors.Is(err, gorm.ErrQueryTimeout) {
+		t.Errorf("should returns ErrQueryTimeout, but got %v", err)
+	} else {
+		var timeoutErr *gorm.QueryTimeoutError
+		if !errors.As(err, &timeoutErr) || timeoutErr.Timeout != 100*time.Millisecond || timeoutErr.SQL == "" {
+			t.Errorf("should returns QueryTimeoutError with timeout and SQL, but got %#v", timeoutErr)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := DB.Session(&gorm.Session{QueryTimeout: time.Minute}).WithContext(ctx).Exec(slowSQL()).Error; err == nil || errors.Is(err, gorm.ErrQueryTimeout) {
+		t.Errorf("caller's cancellation should not be reported as ErrQueryTimeout, but got %v", err)
+	}
+
+	var count int64
+	if err := tx.Model(&User{}).Count(&count).Error; err != nil {
+		t.Errorf("fast queries should not timeout, but got %v", err)
+	}
+}
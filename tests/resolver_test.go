@@ -0,0 +1,166 @@
+package tests_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/resolver"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestResolver(t *testing.T) {
+	var (
+		dir       = t.TempDir()
+		sourceDSN = filepath.Join(dir, "source.db")
+		replicas  = []string{filepath.Join(dir, "replica_1.db"), filepath.Join(dir, "replica_2.db")}
+		petsDSN   = filepath.Join(dir, "pets.db")
+	)
+
+	for _, dsn := range append([]string{sourceDSN, petsDSN}, replicas...) {
+		db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+		if err != nil {
+			t.Fatalf("failed to open %v, got error %v", dsn, err)
+		}
+
+		if err := db.AutoMigrate(&User{}, &Pet{}); err != nil {
+			t.Fatalf("failed to migrate %v, got error %v", dsn, err)
+		}
+	}
+
+	db, err := gorm.Open(sqlite.Open(sourceDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open source, got error %v", err)
+	}
+
+	if err := db.Use(resolver.Register(resolver.Config{
+		Replicas: []gorm.Dialector{sqlite.Open(replicas[0]), sqlite.Open(replicas[1])},
+		Policy:   resolver.RoundRobinPolicy(),
+	}).Register(resolver.Config{
+		Sources: []gorm.Dialector{sqlite.Open(petsDSN)},
+	}, &Pet{})); err != nil {
+		t.Fatalf("failed to use resolver, got error %v", err)
+	}
+
+	user := User{Name: "resolver"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	var count int64
+	db.Model(&User{}).Where("name = ?", user.Name).Count(&count)
+	if count != 0 {
+		t.Errorf("query should be routed to replica, but found %v users", count)
+	}
+
+	var result User
+	if err := db.Clauses(resolver.Write).First(&result, "name = ?", user.Name).Error; err != nil {
+		t.Errorf("query with resolver.Write should be routed to source, got error %v", err)
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("name", "resolver_tx").Error; err != nil {
+			return err
+		}
+		return tx.First(&result, "name = ?", "resolver_tx").Error
+	}); err != nil {
+		t.Errorf("statements in transaction should be routed to source, got error %v", err)
+	}
+
+	for _, dsn := range replicas {
+		replica, _ := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+		replica.Create(&User{Name: "resolver_replica"})
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := db.First(&result, "name = ?", "resolver_replica").Error; err != nil {
+			t.Errorf("query should be routed to replicas, got error %v", err)
+		}
+	}
+
+	if err := db.Create(&Pet{Name: "resolver_pet", UserID: &user.ID}).Error; err != nil {
+		t.Fatalf("failed to create pet, got error %v", err)
+	}
+
+	pets, _ := gorm.Open(sqlite.Open(petsDSN), &gorm.Config{})
+	if err := pets.First(&Pet{}, "name = ?", "resolver_pet").Error; err != nil {
+		t.Errorf("pet should be created in pets source, got error %v", err)
+	}
+
+	source, _ := gorm.Open(sqlite.Open(sourceDSN), &gorm.Config{})
+	if err := source.First(&Pet{}, "name = ?", "resolver_pet").Error; err == nil {
+		t.Errorf("pet should not be created in default source")
+	}
+
+	if err := db.Exec("UPDATE users SET age = ? WHERE name = ?", 18, "resolver_tx").Error; err != nil {
+		t.Fatalf("failed to exec raw sql, got error %v", err)
+	}
+
+	if err := source.First(&result, "name = ? AND age = ?", "resolver_tx", 18).Error; err != nil {
+		t.Errorf("raw sql should be routed to source, got error %v", err)
+	}
+}
+
+func TestResolverWithPreparedStmt(t *testing.T) {
+	var (
+		dir        = t.TempDir()
+		sourceDSN  = filepath.Join(dir, "source.db")
+		replicaDSN = filepath.Join(dir, "replica.db")
+	)
+
+	for _, dsn := range []string{sourceDSN, replicaDSN} {
+		db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+		if err != nil {
+			t.Fatalf("failed to open %v, got error %v", dsn, err)
+		}
+
+		if err := db.AutoMigrate(&User{}); err != nil {
+			t.Fatalf("failed to migrate %v, got error %v", dsn, err)
+		}
+	}
+
+	replica, _ := gorm.Open(sqlite.Open(replicaDSN), &gorm.Config{})
+	replica.Create(&User{Name: "resolver_prepared_replica"})
+
+	for _, prepareStmt := range []bool{true, false} {
+		db, err := gorm.Open(sqlite.Open(sourceDSN), &gorm.Config{PrepareStmt: prepareStmt})
+		if err != nil {
+			t.Fatalf("failed to open source, got error %v", err)
+		}
+
+		if err := db.Use(resolver.Register(resolver.Config{
+			Replicas: []gorm.Dialector{sqlite.Open(replicaDSN)},
+		})); err != nil {
+			t.Fatalf("failed to use resolver, got error %v", err)
+		}
+
+		if !prepareStmt {
+			db = db.Session(&gorm.Session{PrepareStmt: true})
+		}
+
+		user := User{Name: "resolver_prepared"}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("failed to create user, got error %v", err)
+		}
+
+		var result User
+		if err := db.First(&result, "name = ?", "resolver_prepared_replica").Error; err != nil {
+			t.Errorf("prepared query should be routed to replica, got error %v", err)
+		}
+
+		if err := db.First(&result, "name = ?", user.Name).Error; err == nil {
+			t.Errorf("prepared query should not be routed to source")
+		}
+
+		if err := db.Clauses(resolver.Write).First(&result, "name = ?", user.Name).Error; err != nil {
+			t.Errorf("prepared query with resolver.Write should be routed to source, got error %v", err)
+		}
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return tx.First(&result, "name = ?", user.Name).Error
+		}); err != nil {
+			t.Errorf("prepared statements in transaction should be routed to source, got error %v", err)
+		}
+	}
+}
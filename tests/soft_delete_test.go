@@ -3,6 +3,7 @@ package tests_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
 	. "gorm.io/gorm/utils/tests"
@@ -42,3 +43,193 @@ func TestSoftDelete(t *testing.T) {
 		t.Errorf("Can't find permanently deleted record")
 	}
 }
+
+type SoftDeleteRemovedAt struct {
+	ID        uint
+	Name      string
+	RemovedAt gorm.DeletedAt
+}
+
+type SoftDeleteFlag struct {
+	ID        uint
+	Name      string
+	IsDeleted gorm.DeletedFlag
+}
+
+type SoftDeleteUnix struct {
+	ID        uint
+	Name      string           `gorm:"unique_index:idx_soft_delete_unix_name"`
+	DeletedAt gorm.DeletedUnix `gorm:"unique_index:idx_soft_delete_unix_name"`
+}
+
+type SoftDeleteUnixMilli struct {
+	ID        uint
+	Name      string
+	DeletedAt gorm.DeletedUnixMilli
+}
+
+type SoftDeleteFlagAt struct {
+	ID        uint
+	Name      string
+	IsDeleted gorm.DeletedFlagAt `gorm:"deletedAtField:RemovedAt"`
+	RemovedAt *time.Time
+}
+
+func TestSoftDeleteTypes(t *testing.T) {
+	models := []interface{}{
+		&SoftDeleteRemovedAt{Name: "soft_delete"}, &SoftDeleteFlag{Name: "soft_delete"}, &SoftDeleteUnix{Name: "soft_delete"},
+		&SoftDeleteUnixMilli{Name: "soft_delete"}, &SoftDeleteFlagAt{Name: "soft_delete"},
+	}
+	DB.Migrator().DropTable(models...)
+	if err := DB.AutoMigrate(models...); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	for _, model := range models {
+		if err := DB.Create(model).Error; err != nil {
+			t.Fatalf("failed to create %T, got error %v", model, err)
+		}
+
+		var count int64
+		if DB.Model(model).Where("name = ?", "soft_delete").Count(&count); count != 1 {
+			t.Errorf("should find created %T, got %v", model, count)
+		}
+
+		if err := DB.Where("name = ?", "soft_delete").Delete(model).Error; err != nil {
+			t.Fatalf("failed to soft delete %T, got error %v", model, err)
+		}
+
+		if DB.Model(model).Where("name = ?", "soft_delete").Count(&count); count != 0 {
+			t.Errorf("should not find soft deleted %T, got %v", model, count)
+		}
+
+		if DB.Unscoped().Model(model).Where("name = ?", "soft_delete").Count(&count); count != 1 {
+			t.Errorf("should find soft deleted %T with Unscoped, got %v", model, count)
+		}
+	}
+
+	var flagAt SoftDeleteFlagAt
+	if err := DB.Unscoped().First(&flagAt, "name = ?", "soft_delete").Error; err != nil || !bool(flagAt.IsDeleted) || flagAt.RemovedAt == nil {
+		t.Errorf("deleting time should be saved to RemovedAt, got %+v, %v", flagAt, err)
+	}
+
+	var unix SoftDeleteUnix
+	if err := DB.Unscoped().First(&unix, "name = ?", "soft_delete").Error; err != nil || unix.DeletedAt == 0 {
+		t.Errorf("deleting time should be saved as unix seconds, got %+v, %v", unix, err)
+	}
+
+	if err := DB.Create(&SoftDeleteUnix{Name: "soft_delete"}).Error; err != nil {
+		t.Errorf("soft deleted records should not conflict with unique index, got error %v", err)
+	}
+}
+
+type SoftDeleteComposite struct {
+	TenantID  uint   `gorm:"primary_key"`
+	Code      string `gorm:"primary_key"`
+	Name      string
+	Restored  int `gorm:"-"`
+	DeletedAt gorm.DeletedAt
+}
+
+func (s *SoftDeleteComposite) BeforeUpdate(tx *gorm.DB) error {
+	s.Restored++
+	return nil
+}
+
+func TestRestoreAndOnlyDeleted(t *testing.T) {
+	users := []User{*GetUser("restore-1", Config{}), *GetUser("restore-2", Config{}), *GetUser("restore-3", Config{})}
+	DB.Create(&users)
+
+	if err := DB.Delete(&users).Error; err != nil {
+		t.Fatalf("failed to delete users, got error %v", err)
+	}
+
+	var deleted []User
+	if err := DB.OnlyDeleted().Where("name LIKE ?", "restore-%").Find(&deleted).Error; err != nil || len(deleted) != 3 {
+		t.Fatalf("should find soft deleted users, got %v, %v", len(deleted), err)
+	}
+
+	var count int64
+	if DB.OnlyDeleted().Model(&User{}).Where("name LIKE ?", "restore-%").Count(&count); count != 3 {
+		t.Errorf("should count soft deleted users, got %v", count)
+	}
+
+	if err := DB.Restore(&users[0]).Error; err != nil {
+		t.Fatalf("failed to restore user, got error %v", err)
+	}
+
+	if err := DB.First(&User{}, users[0].ID).Error; err != nil {
+		t.Errorf("should find restored user, got error %v", err)
+	}
+
+	if users[0].DeletedAt.Valid {
+		t.Errorf("restored user's DeletedAt should be cleared")
+	}
+
+	if err := DB.Restore(&User{}, "name = ?", users[1].Name).Error; err != nil {
+		t.Fatalf("failed to restore user with conditions, got error %v", err)
+	}
+
+	if DB.Model(&User{}).Where("name LIKE ?", "restore-%").Count(&count); count != 2 {
+		t.Errorf("should find 2 restored users, got %v", count)
+	}
+
+	if err := DB.OnlyDeleted().ForceDelete(&User{}, "name LIKE ?", "restore-%").Error; err != nil {
+		t.Fatalf("failed to force delete user, got error %v", err)
+	}
+
+	if DB.Unscoped().Model(&User{}).Where("name LIKE ?", "restore-%").Count(&count); count != 2 {
+		t.Errorf("only soft deleted users should be force deleted, got %v", count)
+	}
+
+	restored := users[:2]
+	if err := DB.ForceDelete(&restored).Error; err != nil {
+		t.Fatalf("failed to force delete users, got error %v", err)
+	}
+
+	if DB.Unscoped().Model(&User{}).Where("name LIKE ?", "restore-%").Count(&count); count != 0 {
+		t.Errorf("users should be force deleted, got %v", count)
+	}
+
+	if err := DB.Restore(&Language{}, "code = ?", "restore").Error; !errors.Is(err, gorm.ErrMissingSoftDeleteField) {
+		t.Errorf("should returns ErrMissingSoftDeleteField, but got %v", err)
+	}
+}
+
+func TestRestoreCompositePrimaryKeys(t *testing.T) {
+	DB.Migrator().DropTable(&SoftDeleteComposite{})
+	if err := DB.AutoMigrate(&SoftDeleteComposite{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	records := []SoftDeleteComposite{{TenantID: 1, Code: "code", Name: "composite"}, {TenantID: 2, Code: "code", Name: "composite"}}
+	DB.Create(&records)
+	DB.Delete(&records)
+
+	unrelated := SoftDeleteComposite{TenantID: 3, Code: "other", Name: "unrelated"}
+	DB.Create(&unrelated)
+	DB.Delete(&unrelated)
+
+	restoring := records[1:]
+	if err := DB.Restore(&restoring).Error; err != nil {
+		t.Fatalf("failed to restore, got error %v", err)
+	}
+
+	if restoring[0].Restored != 1 {
+		t.Errorf("update hooks should be called when restoring")
+	}
+
+	var results []SoftDeleteComposite
+	if DB.Find(&results, "name = ?", "composite"); len(results) != 1 || results[0].TenantID != 2 {
+		t.Errorf("only the restored record should be found, got %+v", results)
+	}
+
+	if DB.OnlyDeleted().Find(&results, "name = ?", "composite"); len(results) != 1 || results[0].TenantID != 1 {
+		t.Errorf("only the soft deleted record should be found, got %+v", results)
+	}
+
+	var count int64
+	if DB.Model(&SoftDeleteComposite{}).Where("name = ?", "unrelated").Count(&count); count != 0 {
+		t.Errorf("unrelated soft deleted records should not be restored, got %v", count)
+	}
+}
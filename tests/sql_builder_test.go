@@ -4,7 +4,9 @@ import (
 	"strings"
 	"testing"
 
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -163,3 +165,25 @@ func TestGroupConditions(t *testing.T) {
 		t.Errorf("expects: %v, got %v", expects, result)
 	}
 }
+
+func TestSubQueryBindVars(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("user=gorm password=gorm dbname=gorm"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("failed to open postgres dialector, got error %v", err)
+	}
+
+	subQuery := db.Table("orders").Select("AVG(amount)").Where("state = ?", "paid")
+	stmt := db.Table("(?) AS o", db.Table("orders").Where("user_id = ?", 1)).
+		Where("amount > ?", 10).Where("amount > (?)", subQuery).
+		Where(clause.IN{Column: "id", Values: []interface{}{db.Raw("SELECT order_id FROM items WHERE price > ? AND count < ?", 100, 3)}}).
+		Find(&[]map[string]interface{}{}).Statement
+
+	expected := `SELECT * FROM (SELECT * FROM "orders" WHERE user_id = $1) AS o WHERE amount > $2 AND amount > (SELECT AVG(amount) FROM "orders" WHERE state = $3) AND "id" IN (SELECT order_id FROM items WHERE price > $4 AND count < $5)`
+	if stmt.SQL.String() != expected {
+		t.Errorf("expects: %v, got %v", expected, stmt.SQL.String())
+	}
+
+	if len(stmt.Vars) != 5 {
+		t.Errorf("expects 5 vars, got %v", stmt.Vars)
+	}
+}
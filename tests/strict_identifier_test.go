@@ -0,0 +1,70 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestStrictIdentifier(t *testing.T) {
+	db, err := gorm.Open(DB.Dialector, &gorm.Config{StrictIdentifier: true})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	users := []User{*GetUser("strict_identifier_1", Config{Account: true}), *GetUser("strict_identifier_2", Config{})}
+	users[1].Age = 20
+	DB.Create(&users)
+
+	var results []User
+	if err := db.Where("name LIKE ?", "strict_identifier%").Order("age desc, name").Find(&results).Error; err != nil {
+		t.Fatalf("failed to order by known columns, got error %v", err)
+	} else if len(results) != 2 || results[0].Name != "strict_identifier_2" {
+		t.Errorf("records should be ordered by age desc, got %+v", results)
+	}
+
+	if err := db.Where("name LIKE ?", "strict_identifier%").Order("users.age").Select("users.name, age").Group("users.name, age").Find(&results).Error; err != nil {
+		t.Errorf("failed to query with table qualified columns, got error %v", err)
+	}
+
+	if err := db.Joins("Account").Where("users.name = ?", "strict_identifier_1").Order("Account.number").Find(&results).Error; err != nil || len(results) != 1 {
+		t.Errorf("failed to order by joined association's column, got %v records, error %v", len(results), err)
+	}
+
+	if err := db.Order(clause.Expr{SQL: "CASE WHEN age > ? THEN 0 ELSE 1 END", Vars: []interface{}{19}}).Select(clause.Expr{SQL: "name, age + 1 AS age"}).Find(&results, "name LIKE ?", "strict_identifier%").Error; err != nil {
+		t.Errorf("clause.Expr should be allowed, got error %v", err)
+	} else if len(results) != 2 || results[0].Name != "strict_identifier_2" || results[0].Age != 21 {
+		t.Errorf("records should be ordered by expression, got %+v", results)
+	}
+
+	if err := db.Joins(clause.Expr{SQL: "LEFT JOIN accounts ON accounts.user_id = users.id AND accounts.number <> ?", Vars: []interface{}{""}}).Where("users.name = ?", "strict_identifier_1").Order(clause.Expr{SQL: "users.age"}).Order("name").Find(&results).Error; err != nil || len(results) != 1 {
+		t.Errorf("joins with clause.Expr should be allowed, got %v records, error %v", len(results), err)
+	}
+
+	for name, tx := range map[string]*gorm.DB{
+		"order injection":   db.Order("name; DELETE FROM users"),
+		"order unknown":     db.Order("password desc"),
+		"order not joined":  db.Order("Account.number"),
+		"group unknown":     db.Group("unknown"),
+		"select expression": db.Select("count(*)"),
+		"select unknown":    db.Select([]string{"name", "password"}),
+		"select args":       db.Select("name = ?", "jinzhu"),
+		"joins raw sql":     db.Joins("JOIN accounts ON accounts.user_id = users.id"),
+	} {
+		if err := tx.Find(&results).Error; !errors.Is(err, gorm.ErrInvalidIdentifier) {
+			t.Errorf("%v: should returns ErrInvalidIdentifier, got error %v", name, err)
+		}
+	}
+
+	if err := db.Table("users").Order("name").Find(&[]map[string]interface{}{}).Error; !errors.Is(err, gorm.ErrInvalidIdentifier) {
+		t.Errorf("should returns ErrInvalidIdentifier without model, got error %v", err)
+	}
+
+	var count int64
+	if DB.Model(&User{}).Where("name LIKE ?", "strict_identifier%").Count(&count); count != 2 {
+		t.Errorf("users should not be deleted, got %v", count)
+	}
+}
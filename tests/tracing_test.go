@@ -0,0 +1,142 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/tracing"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type memorySpan struct {
+	name       string
+	parent     *memorySpan
+	attributes map[string]interface{}
+	errors     []error
+	ended      bool
+}
+
+func (s *memorySpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *memorySpan) RecordError(err error) {
+	s.errors = append(s.errors, err)
+}
+
+func (s *memorySpan) End() {
+	s.ended = true
+}
+
+type memorySpanKey struct{}
+
+// memoryExporter in-memory tracer and metrics
+type memoryExporter struct {
+	mutex     sync.Mutex
+	spans     []*memorySpan
+	latencies map[string][]time.Duration
+	errors    map[string]int
+}
+
+func (e *memoryExporter) Start(ctx context.Context, name string) (context.Context, tracing.Span) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	span := &memorySpan{name: name, attributes: map[string]interface{}{}}
+	span.parent, _ = ctx.Value(memorySpanKey{}).(*memorySpan)
+	e.spans = append(e.spans, span)
+	return context.WithValue(ctx, memorySpanKey{}, span), span
+}
+
+func (e *memoryExporter) ObserveLatency(operation, table string, latency time.Duration) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.latencies[operation+":"+table] = append(e.latencies[operation+":"+table], latency)
+}
+
+func (e *memoryExporter) IncErrors(operation, table string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.errors[operation+":"+table]++
+}
+
+func TestTracing(t *testing.T) {
+	db, err := gorm.Open(DB.Dialector, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	exporter := &memoryExporter{latencies: map[string][]time.Duration{}, errors: map[string]int{}}
+	if err := db.Use(tracing.New(tracing.Config{Tracer: exporter, Metrics: exporter})); err != nil {
+		t.Fatalf("failed to use tracing plugin, got error %v", err)
+	}
+
+	user := *GetUser("tracing", Config{Account: true})
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	if len(exporter.spans) != 2 {
+		t.Fatalf("should have spans for creating user and its account, got %v", len(exporter.spans))
+	}
+
+	span, childSpan := exporter.spans[0], exporter.spans[1]
+	if span.name != "gorm.create" || !span.ended || span.attributes[tracing.AttributeTable] != "users" ||
+		span.attributes[tracing.AttributeSystem] != DB.Dialector.Name() || span.attributes[tracing.AttributeRowsAffected] != int64(1) {
+		t.Errorf("invalid span for creating user, got %v %+v", span.name, span.attributes)
+	}
+
+	if statement, _ := span.attributes[tracing.AttributeStatement].(string); statement == "" {
+		t.Errorf("span should have statement")
+	}
+
+	if childSpan.name != "gorm.create" || childSpan.attributes[tracing.AttributeTable] != "accounts" || childSpan.parent != span {
+		t.Errorf("span for creating account should be child of user's span, got %v %+v", childSpan.name, childSpan.attributes)
+	}
+
+	if err := db.Table("non_existing").Find(&[]User{}).Error; err == nil {
+		t.Fatalf("should returns error when querying non existing table")
+	}
+
+	span = exporter.spans[len(exporter.spans)-1]
+	if span.name != "gorm.query" || len(span.errors) != 1 || exporter.errors["query:non_existing"] != 1 {
+		t.Errorf("error should be recorded, got %v %v, errors: %v", span.name, span.errors, exporter.errors)
+	}
+
+	if err := db.First(&User{}, "name = ?", "non_existing").Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("should returns record not found, got %v", err)
+	}
+
+	if span = exporter.spans[len(exporter.spans)-1]; len(span.errors) != 0 || exporter.errors["query:users"] != 0 {
+		t.Errorf("record not found should not be recorded as error, got %v", span.errors)
+	}
+
+	if err := db.Exec("UPDATE users SET age = ? WHERE name = ?", 20, user.Name).Error; err != nil {
+		t.Fatalf("failed to exec raw sql, got error %v", err)
+	}
+
+	for _, key := range []string{"create:users", "create:accounts", "query:non_existing", "query:users", "raw:"} {
+		if len(exporter.latencies[key]) == 0 {
+			t.Errorf("latency of %v should be observed, got %v", key, exporter.latencies)
+		}
+	}
+
+	db, err = gorm.Open(DB.Dialector, &gorm.Config{SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	exporter.spans = nil
+	db.Use(tracing.New(tracing.Config{Tracer: exporter}))
+	if err := db.Create(GetUser("tracing_without_transaction", Config{})).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	if len(exporter.spans) != 1 || !exporter.spans[0].ended || exporter.spans[0].attributes[tracing.AttributeStatement] == "" {
+		t.Errorf("should trace operations without default transaction, got %+v", exporter.spans)
+	}
+}
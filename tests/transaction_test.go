@@ -1,8 +1,12 @@
 package tests_test
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
 	. "gorm.io/gorm/utils/tests"
@@ -262,3 +266,281 @@ func TestNestedTransactionWithBlock(t *testing.T) {
 		t.Fatalf("Should find saved record")
 	}
 }
+
+func TestTransactionWithRetry(t *testing.T) {
+	policy := &gorm.RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+	var attempts int
+	if err := DB.TransactionWithRetry(func(tx *gorm.DB) error {
+		attempts++
+		if err := tx.Create(GetUser("transaction-retry", Config{})).Error; err != nil {
+			return err
+		}
+
+		if attempts < 3 {
+			return fmt.Errorf("failed to update: %w", gorm.ErrSerializationFailure)
+		}
+		return nil
+	}, policy); err != nil {
+		t.Fatalf("transaction should succeed after retries, got error %v", err)
+	}
+
+	var count int64
+	if DB.Model(&User{}).Where("name = ?", "transaction-retry").Count(&count); attempts != 3 || count != 1 {
+		t.Errorf("transaction should be retried until succeed, got %v attempts, %v records", attempts, count)
+	}
+
+	attempts = 0
+	if err := DB.TransactionWithRetry(func(tx *gorm.DB) error {
+		attempts++
+		return gorm.ErrDeadlock
+	}, policy); !errors.Is(err, gorm.ErrDeadlock) || attempts != 4 {
+		t.Errorf("transaction should be retried at most 3 times, got %v attempts, error %v", attempts, err)
+	}
+
+	attempts = 0
+	if err := DB.TransactionWithRetry(func(tx *gorm.DB) error {
+		attempts++
+		return errors.New("unretryable error")
+	}, nil); err == nil || attempts != 1 {
+		t.Errorf("unretryable error should not be retried, got %v attempts, error %v", attempts, err)
+	}
+
+	attempts = 0
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		return tx.TransactionWithRetry(func(tx *gorm.DB) error {
+			attempts++
+			return gorm.ErrLockTimeout
+		}, policy)
+	}); !errors.Is(err, gorm.ErrLockTimeout) || attempts != 1 {
+		t.Errorf("nested transaction should not be retried, got %v attempts, error %v", attempts, err)
+	}
+
+	attempts = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := DB.WithContext(ctx).TransactionWithRetry(func(tx *gorm.DB) error {
+		attempts++
+		cancel()
+		return gorm.ErrSerializationFailure
+	}, &gorm.RetryPolicy{MinBackoff: time.Minute, MaxBackoff: time.Minute}); !errors.Is(err, context.Canceled) || attempts != 1 {
+		t.Errorf("should stop retrying when context canceled, got %v attempts, error %v", attempts, err)
+	}
+}
+
+type TxHookProduct struct {
+	ID                 uint
+	Name               string
+	AfterCommitCalls   int `gorm:"-"`
+	AfterRollbackCalls int `gorm:"-"`
+}
+
+type TxHookLog struct {
+	ID        uint
+	ProductID uint
+	Message   string
+}
+
+func (l *TxHookLog) BeforeCreate(tx *gorm.DB) error {
+	l.Message = "created"
+	return nil
+}
+
+func (p *TxHookProduct) AfterCommit(tx *gorm.DB) error {
+	p.AfterCommitCalls++
+	if err := tx.Create(&TxHookLog{ProductID: p.ID}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&TxHookProduct{}).Where("id = ?", p.ID).Update("name", p.Name+"_committed").Error
+}
+
+func (p *TxHookProduct) AfterRollback(tx *gorm.DB) error {
+	p.AfterRollbackCalls++
+	return nil
+}
+
+func TestTransactionAfterCommitAndRollback(t *testing.T) {
+	if err := DB.AfterCommit(func() {}).Error; !errors.Is(err, gorm.ErrInvalidTransaction) {
+		t.Errorf("should returns ErrInvalidTransaction when not in transaction, but got %v", err)
+	}
+
+	var calls []string
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		tx.AfterCommit(func() { calls = append(calls, "commit") })
+		tx.AfterRollback(func() { calls = append(calls, "rollback") })
+
+		tx.Transaction(func(tx2 *gorm.DB) error {
+			tx2.AfterCommit(func() { calls = append(calls, "nested rollback commit") })
+			return errors.New("rollback nested")
+		})
+
+		tx.Transaction(func(tx2 *gorm.DB) error {
+			tx2.AfterCommit(func() { calls = append(calls, "nested commit") })
+			return nil
+		})
+
+		if len(calls) != 0 {
+			t.Errorf("callbacks should not be called before commit, but got %v", calls)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("no error should happen, but got %v", err)
+	}
+
+	if fmt.Sprint(calls) != "[commit nested commit]" {
+		t.Errorf("callbacks after commit are not correct, got %v", calls)
+	}
+
+	calls = nil
+	DB.Transaction(func(tx *gorm.DB) error {
+		tx.AfterCommit(func() { calls = append(calls, "commit") })
+		tx.AfterRollback(func() { calls = append(calls, "rollback") })
+		return errors.New("rollback")
+	})
+
+	if fmt.Sprint(calls) != "[rollback]" {
+		t.Errorf("callbacks after rollback are not correct, got %v", calls)
+	}
+}
+
+func TestTransactionHooks(t *testing.T) {
+	DB.Migrator().DropTable(&TxHookProduct{}, &TxHookLog{})
+	if err := DB.AutoMigrate(&TxHookProduct{}, &TxHookLog{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	product := TxHookProduct{Name: "hook"}
+	if err := DB.Create(&product).Error; err != nil {
+		t.Fatalf("failed to create product, got error %v", err)
+	}
+
+	if product.AfterCommitCalls != 1 || product.AfterRollbackCalls != 0 {
+		t.Errorf("AfterCommit should be called after create, got %v, %v", product.AfterCommitCalls, product.AfterRollbackCalls)
+	}
+
+	var result TxHookProduct
+	if DB.First(&result, product.ID); result.Name != "hook_committed" {
+		t.Errorf("AfterCommit hook should be able to use db, got name %v", result.Name)
+	}
+
+	var log TxHookLog
+	if err := DB.First(&log, "product_id = ?", product.ID).Error; err != nil {
+		t.Fatalf("AfterCommit hook should create log, got error %v", err)
+	} else if log.Message != "created" {
+		t.Errorf("hooks of records created in AfterCommit hook should be called, got message %v", log.Message)
+	}
+
+	product2 := TxHookProduct{Name: "hook2"}
+	tx := DB.Begin()
+	if err := tx.Create(&product2).Error; err != nil {
+		t.Fatalf("failed to create product, got error %v", err)
+	}
+
+	if product2.AfterCommitCalls != 0 || product2.AfterRollbackCalls != 0 {
+		t.Errorf("hooks should not be called before transaction finished, got %v, %v", product2.AfterCommitCalls, product2.AfterRollbackCalls)
+	}
+
+	tx.Rollback()
+
+	if product2.AfterCommitCalls != 0 || product2.AfterRollbackCalls != 1 {
+		t.Errorf("AfterRollback should be called after rollback, got %v, %v", product2.AfterCommitCalls, product2.AfterRollbackCalls)
+	}
+}
+
+func TestTransactionUnwrapConnPool(t *testing.T) {
+	tx := DB.Begin()
+	defer tx.Rollback()
+
+	unwrapper, ok := tx.Statement.ConnPool.(gorm.ConnPoolUnwrapper)
+	if !ok {
+		t.Fatalf("conn pool of transaction should be unwrappable, got %T", tx.Statement.ConnPool)
+	}
+
+	if _, ok := unwrapper.Unwrap().(*sql.Tx); !ok {
+		t.Errorf("conn pool of transaction should wrap *sql.Tx, got %T", unwrapper.Unwrap())
+	}
+}
+
+func TestTransactionWithPropagation(t *testing.T) {
+	if err := DB.TransactionWithPropagation(gorm.PropagationMandatory, func(tx *gorm.DB) error { return nil }); !errors.Is(err, gorm.ErrMissingTransaction) {
+		t.Errorf("should returns ErrMissingTransaction, but got %v", err)
+	}
+
+	var (
+		user  = *GetUser("transaction-propagation", Config{})
+		user1 = *GetUser("transaction-propagation-1", Config{})
+		user2 = *GetUser("transaction-propagation-2", Config{})
+	)
+
+	DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.TransactionWithPropagation(gorm.PropagationRequiresNew, func(tx1 *gorm.DB) error {
+			return tx1.Create(&user).Error
+		}); err != nil {
+			t.Fatalf("no error should return, but got %v", err)
+		}
+
+		if err := tx.TransactionWithPropagation(gorm.PropagationNever, func(tx1 *gorm.DB) error { return nil }); !errors.Is(err, gorm.ErrExistingTransaction) {
+			t.Errorf("should returns ErrExistingTransaction, but got %v", err)
+		}
+
+		if err := tx.TransactionWithPropagation(gorm.PropagationMandatory, func(tx1 *gorm.DB) error {
+			return tx1.Create(&user1).Error
+		}); err != nil {
+			t.Fatalf("no error should return, but got %v", err)
+		}
+
+		if err := tx.TransactionWithPropagation(gorm.PropagationRequired, func(tx1 *gorm.DB) error {
+			return tx1.Create(&user2).Error
+		}); err != nil {
+			t.Fatalf("no error should return, but got %v", err)
+		}
+
+		return errors.New("rollback")
+	})
+
+	if err := DB.First(&User{}, "name = ?", user.Name).Error; err != nil {
+		t.Errorf("record created with PropagationRequiresNew should be committed, got %v", err)
+	}
+
+	if err := DB.First(&User{}, "name = ?", user1.Name).Error; err == nil {
+		t.Errorf("record created with PropagationMandatory should be rollbacked")
+	}
+
+	if err := DB.First(&User{}, "name = ?", user2.Name).Error; err == nil {
+		t.Errorf("record created with PropagationRequired should be rollbacked")
+	}
+}
+
+func TestRecursiveNestedTransaction(t *testing.T) {
+	var names []string
+	var fc func(tx *gorm.DB) error
+	fc = func(tx *gorm.DB) error {
+		depth := len(names)
+		user := *GetUser(fmt.Sprintf("transaction-recursive-%v", depth), Config{})
+		names = append(names, user.Name)
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		if depth < 2 {
+			tx.Transaction(fc)
+		}
+
+		if depth == 2 {
+			return errors.New("rollback innermost")
+		}
+		return nil
+	}
+
+	if err := DB.Transaction(fc); err != nil {
+		t.Fatalf("no error should return, but got %v", err)
+	}
+
+	for idx, name := range names {
+		err := DB.First(&User{}, "name = ?", name).Error
+		if idx < 2 && err != nil {
+			t.Errorf("record %v should be committed, got %v", name, err)
+		} else if idx == 2 && err == nil {
+			t.Errorf("record %v should be rollbacked", name)
+		}
+	}
+}
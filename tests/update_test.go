@@ -2,12 +2,14 @@ package tests_test
 
 import (
 	"errors"
+	"fmt"
 	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -523,3 +525,125 @@ func TestUpdatesTableWithIgnoredValues(t *testing.T) {
 		t.Errorf("element's ignored field should not be updated")
 	}
 }
+
+func TestUpdateBatch(t *testing.T) {
+	users := []User{*GetUser("update_batch_1", Config{}), *GetUser("update_batch_2", Config{}), *GetUser("update_batch_3", Config{})}
+	DB.Create(&users)
+
+	for idx := range users {
+		users[idx].Age = uint(100 + idx)
+		users[idx].Name = fmt.Sprintf("%v_updated", users[idx].Name)
+	}
+
+	result := DB.Model(&User{}).UpdateBatch(users, "Age")
+	if result.Error != nil {
+		t.Fatalf("failed to update batch, got error %v", result.Error)
+	} else if result.RowsAffected != 3 {
+		t.Errorf("rows affected should be 3, got %v", result.RowsAffected)
+	}
+
+	for _, user := range users {
+		var result User
+		DB.First(&result, user.ID)
+		if result.Age != user.Age || result.Name == user.Name {
+			t.Errorf("only selected columns should be updated with their own values, got %v, %v", result.Age, result.Name)
+		}
+	}
+
+	var batchRowsAffected []int64
+	tx := DB.Session(&gorm.Session{})
+	tx.UpdateBatchSize = 2
+	result = tx.UpdateInBatches(&users, func(tx *gorm.DB, batch int) error {
+		batchRowsAffected = append(batchRowsAffected, tx.RowsAffected)
+		return nil
+	})
+	if result.Error != nil {
+		t.Fatalf("failed to update batch, got error %v", result.Error)
+	} else if result.RowsAffected != 3 {
+		t.Errorf("rows affected should be 3, got %v", result.RowsAffected)
+	}
+
+	if fmt.Sprint(batchRowsAffected) != "[2 1]" {
+		t.Errorf("rows affected of batches should be [2 1], got %v", batchRowsAffected)
+	}
+
+	for _, user := range users {
+		var result User
+		DB.First(&result, user.ID)
+		if result.Age != user.Age || result.Name != user.Name {
+			t.Errorf("all fields should be updated, got %v, %v", result.Age, result.Name)
+		}
+	}
+
+	if err := DB.UpdateBatch([]User{{Name: "no primary key"}}).Error; !errors.Is(err, gorm.ErrorPrimaryKeyRequired) {
+		t.Errorf("should returns ErrorPrimaryKeyRequired, but got %v", err)
+	}
+}
+
+func TestUpdateBatchWithCompositePrimaryKeys(t *testing.T) {
+	if DB.Dialector.Name() == "sqlserver" {
+		t.Skip("sqlserver doesn't support IN with multiple columns")
+	}
+
+	type UpdateBatchPrice struct {
+		Store string `gorm:"primary_key"`
+		SKU   string `gorm:"primary_key"`
+		Price float64
+	}
+
+	DB.Migrator().DropTable(&UpdateBatchPrice{})
+	if err := DB.AutoMigrate(&UpdateBatchPrice{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	prices := []UpdateBatchPrice{{Store: "a", SKU: "1", Price: 1}, {Store: "a", SKU: "2", Price: 2}, {Store: "b", SKU: "1", Price: 3}}
+	DB.Create(&prices)
+
+	prices[0].Price, prices[2].Price = 10, 30
+	if err := DB.UpdateBatch([]UpdateBatchPrice{prices[0], prices[2]}, "price").Error; err != nil {
+		t.Fatalf("failed to update batch, got error %v", err)
+	}
+
+	var results []UpdateBatchPrice
+	DB.Order("store, sku").Find(&results)
+	if fmt.Sprint(results) != fmt.Sprint(prices) {
+		t.Errorf("prices should be updated, expects %v, got %v", prices, results)
+	}
+}
+
+func TestUpdateReturning(t *testing.T) {
+	if DB.Dialector.Name() != "postgres" {
+		t.Skip()
+	}
+
+	users := []User{*GetUser("update-returning-1", Config{}), *GetUser("update-returning-2", Config{}), *GetUser("update-returning-3", Config{})}
+	DB.Create(&users)
+
+	var results []User
+	if err := DB.Model(&results).Clauses(clause.Returning{}).Where("name LIKE ?", "update-returning-%").Update("age", gorm.Expr("age + ?", 100)).Error; err != nil {
+		t.Fatalf("failed to update with returning, got error %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("updated rows should be returned, got %v", len(results))
+	}
+
+	for _, result := range results {
+		if result.Name == "" || result.Age < 100 {
+			t.Errorf("returned values should be scanned into the model, got %+v", result)
+		}
+	}
+
+	users = users[:2]
+	if err := DB.Model(&users).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "age"}}}).Updates(map[string]interface{}{"age": gorm.Expr("age + ?", 1)}).Error; err != nil {
+		t.Fatalf("failed to update with returning, got error %v", err)
+	}
+
+	for _, user := range users {
+		var result User
+		DB.First(&result, user.ID)
+		if user.Age != result.Age || user.Age < 101 {
+			t.Errorf("returned age should be scanned into the matched element, expects %v, got %v", result.Age, user.Age)
+		}
+	}
+}
@@ -90,6 +90,32 @@ func TestUpsertSlice(t *testing.T) {
 	}
 }
 
+func TestUpsertWithUpdateAll(t *testing.T) {
+	user := *GetUser("upsert_update_all", Config{})
+	if err := DB.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user, got error %v", err)
+	}
+
+	user2 := user
+	user2.Name = "upsert_update_all_new"
+	user2.Age = 100
+	user2.CreatedAt = user.CreatedAt.Add(-time.Hour)
+	if err := DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&user2).Error; err != nil {
+		t.Fatalf("failed to upsert user, got error %v", err)
+	}
+
+	var result User
+	if err := DB.First(&result, user.ID).Error; err != nil {
+		t.Fatalf("failed to query user, got error %v", err)
+	}
+
+	if result.Name != user2.Name || result.Age != user2.Age {
+		t.Errorf("should update all columns on conflict, got %+v", result)
+	}
+
+	AssertEqual(t, result.CreatedAt, user.CreatedAt)
+}
+
 func TestUpsertWithSave(t *testing.T) {
 	langs := []Language{
 		{Code: "upsert-save-1", Name: "Upsert-save-1"},
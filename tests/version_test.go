@@ -0,0 +1,257 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type VersionedItem struct {
+	ID      uint
+	Name    string
+	Version gorm.Version
+	Parts   []VersionedPart
+}
+
+type VersionedPart struct {
+	ID              uint
+	VersionedItemID uint
+	Name            string
+	Version         gorm.Version
+}
+
+func TestVersion(t *testing.T) {
+	DB.Migrator().DropTable(&VersionedItem{}, &VersionedPart{})
+	if err := DB.AutoMigrate(&VersionedItem{}, &VersionedPart{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	item := VersionedItem{Name: "version", Parts: []VersionedPart{{Name: "part_1"}, {Name: "part_2"}}}
+	if err := DB.Create(&item).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	if item.Version.Int64 != 1 {
+		t.Errorf("version should be initialized to 1, but got %v", item.Version.Int64)
+	}
+
+	for _, part := range item.Parts {
+		if part.Version.Int64 != 1 {
+			t.Errorf("association's version should be initialized to 1, but got %v", part.Version.Int64)
+		}
+	}
+
+	var stale VersionedItem
+	DB.First(&stale, item.ID)
+
+	item.Name = "version_saved"
+	if err := DB.Save(&item).Error; err != nil {
+		t.Fatalf("failed to save, got error %v", err)
+	}
+
+	if item.Version.Int64 != 2 {
+		t.Errorf("version should be increased to 2 after save, but got %v", item.Version.Int64)
+	}
+
+	for _, part := range item.Parts {
+		if part.Version.Int64 != 2 {
+			t.Errorf("association's version should be increased to 2 after save, but got %v", part.Version.Int64)
+		}
+	}
+
+	if err := DB.Model(&item).Updates(map[string]interface{}{"name": "version_updated"}).Error; err != nil {
+		t.Fatalf("failed to update, got error %v", err)
+	}
+
+	if item.Version.Int64 != 3 {
+		t.Errorf("version should be increased to 3 after updates, but got %v", item.Version.Int64)
+	}
+
+	stale.Name = "version_stale"
+	if err := DB.Save(&stale).Error; !errors.Is(err, gorm.ErrStaleObject) {
+		t.Errorf("should returns ErrStaleObject when saving stale object, but got %v", err)
+	}
+
+	if err := DB.Model(&stale).Update("name", "version_stale").Error; !errors.Is(err, gorm.ErrStaleObject) {
+		t.Errorf("should returns ErrStaleObject when updating stale object, but got %v", err)
+	}
+
+	var result VersionedItem
+	DB.First(&result, item.ID)
+	if result.Name != "version_updated" || result.Version.Int64 != 3 {
+		t.Errorf("stale object should not be saved, got %+v", result)
+	}
+
+	result.Parts = item.Parts
+	result.Parts[0].Name = "part_1_updated"
+	partVersion := result.Parts[0].Version.Int64
+	if err := DB.Save(&result.Parts[0]).Error; err != nil {
+		t.Fatalf("failed to save association, got error %v", err)
+	}
+
+	if result.Parts[0].Version.Int64 != partVersion+1 {
+		t.Errorf("association's version should be increased to %v, but got %v", partVersion+1, result.Parts[0].Version.Int64)
+	}
+
+	if err := DB.Model(&VersionedItem{}).Where("id = ?", item.ID).Update("name", "version_without_check").Error; err != nil {
+		t.Fatalf("failed to update without version, got error %v", err)
+	}
+
+	DB.First(&result, item.ID)
+	if result.Name != "version_without_check" || result.Version.Int64 != 4 {
+		t.Errorf("version should be increased when updating without version check, got %+v", result)
+	}
+}
+
+func TestVersionWithSlicesAndAssociations(t *testing.T) {
+	DB.Migrator().DropTable(&VersionedItem{}, &VersionedPart{})
+	if err := DB.AutoMigrate(&VersionedItem{}, &VersionedPart{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	items := []VersionedItem{{Name: "version_slice_1"}, {Name: "version_slice_2"}}
+	if err := DB.Create(&items).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	var stales []VersionedItem
+	DB.Order("id").Where("id IN ?", []uint{items[0].ID, items[1].ID}).Find(&stales)
+
+	items[0].Name = "version_slice_saved"
+	if err := DB.Save(&items).Error; err != nil {
+		t.Fatalf("failed to save slice, got error %v", err)
+	}
+
+	if items[0].Version.Int64 != 2 || items[1].Version.Int64 != 2 {
+		t.Errorf("versions should be increased to 2 after saving slice, but got %v, %v", items[0].Version.Int64, items[1].Version.Int64)
+	}
+
+	stales[1].Name = "version_slice_stale"
+	if err := DB.Save(&stales).Error; !errors.Is(err, gorm.ErrStaleObject) {
+		t.Errorf("should returns ErrStaleObject when saving stale slice, but got %v", err)
+	}
+
+	var results []VersionedItem
+	DB.Order("id").Where("id IN ?", []uint{items[0].ID, items[1].ID}).Find(&results)
+	if len(results) != 2 || results[0].Name != "version_slice_saved" || results[0].Version.Int64 != 2 || results[1].Name != "version_slice_2" || results[1].Version.Int64 != 2 {
+		t.Errorf("stale slice should not be saved, got %+v", results)
+	}
+
+	item := VersionedItem{Name: "version_association", Parts: []VersionedPart{{Name: "part"}}}
+	if err := DB.Create(&item).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	var stalePart VersionedPart
+	DB.First(&stalePart, item.Parts[0].ID)
+
+	if err := DB.Save(&item).Error; err != nil {
+		t.Fatalf("failed to save with associations, got error %v", err)
+	}
+
+	owner := VersionedItem{Name: "version_association_stale", Parts: []VersionedPart{stalePart}}
+	if err := DB.Create(&owner).Error; !errors.Is(err, gorm.ErrStaleObject) {
+		t.Errorf("should returns ErrStaleObject when saving stale association, but got %v", err)
+	}
+
+	var part VersionedPart
+	DB.First(&part, item.Parts[0].ID)
+	if part.VersionedItemID != item.ID || part.Version.Int64 != 2 {
+		t.Errorf("stale association should not be saved, got %+v", part)
+	}
+}
+
+func TestUpdateBatchWithVersion(t *testing.T) {
+	DB.Migrator().DropTable(&VersionedItem{}, &VersionedPart{})
+	if err := DB.AutoMigrate(&VersionedItem{}, &VersionedPart{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	item := VersionedItem{Name: "update_batch", Parts: []VersionedPart{{Name: "batch_1"}, {Name: "batch_2"}, {Name: "batch_3"}}}
+	if err := DB.Create(&item).Error; err != nil {
+		t.Fatalf("failed to create item, got error %v", err)
+	}
+
+	parts := item.Parts
+
+	for idx := range parts {
+		parts[idx].Name += "_updated"
+	}
+
+	if err := DB.UpdateBatch(parts, "name").Error; err != nil {
+		t.Fatalf("failed to update batch, got error %v", err)
+	}
+
+	for _, part := range parts {
+		var result VersionedPart
+		DB.First(&result, part.ID)
+		if result.Name != part.Name || result.Version.Int64 != 2 || part.Version.Int64 != 2 {
+			t.Errorf("versions should be increased and written back, got %v, %v, %v", result.Name, result.Version.Int64, part.Version.Int64)
+		}
+	}
+
+	stales := make([]VersionedPart, len(parts))
+	copy(stales, parts)
+	stales[1].Version.Int64 = 1
+	for idx := range stales {
+		stales[idx].Name += "_stale"
+	}
+
+	if err := DB.Session(&gorm.Session{}).UpdateBatch(stales, "name").Error; !errors.Is(err, gorm.ErrStaleObject) {
+		t.Fatalf("should returns ErrStaleObject when updating batch with outdated version, got %v", err)
+	}
+
+	for _, part := range parts {
+		var result VersionedPart
+		DB.First(&result, part.ID)
+		if result.Name != part.Name || result.Version.Int64 != 2 {
+			t.Errorf("batch with outdated version should not be updated, got %v, %v", result.Name, result.Version.Int64)
+		}
+	}
+
+	if stales[0].Version.Int64 != 2 {
+		t.Errorf("versions should not be written back when failed to update batch, got %v", stales[0].Version.Int64)
+	}
+}
+
+type Revision uint32
+
+func (Revision) CreateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{gorm.VersionCreateClause{Field: f}}
+}
+
+func (Revision) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{gorm.VersionUpdateClause{Field: f}}
+}
+
+type RevisionedItem struct {
+	ID       uint
+	Name     string
+	Revision Revision
+}
+
+func TestVersionWithIntegerKinds(t *testing.T) {
+	DB.Migrator().DropTable(&RevisionedItem{})
+	if err := DB.AutoMigrate(&RevisionedItem{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	item := RevisionedItem{Name: "revision"}
+	if err := DB.Create(&item).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	item.Name = "revision_saved"
+	if err := DB.Save(&item).Error; err != nil || item.Revision != 2 {
+		t.Fatalf("version of integer kinds should be increased after save, got %v, %v", item.Revision, err)
+	}
+
+	stale := item
+	stale.Revision = 1
+	if err := DB.Model(&stale).Update("name", "revision_stale").Error; !errors.Is(err, gorm.ErrStaleObject) {
+		t.Errorf("should returns ErrStaleObject when updating with outdated version, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package tests_test
+
+import (
+	"testing"
+
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestWith(t *testing.T) {
+	users := []User{
+		{Name: "with_1", Age: 10},
+		{Name: "with_2", Age: 20},
+		{Name: "with_3", Age: 30},
+	}
+	DB.Create(&users)
+
+	var results []User
+	if err := DB.With("with_adults", DB.Model(&User{}).Where("name LIKE ? AND age >= ?", "with_%", 20)).
+		Table("with_adults").Order("age").Find(&results).Error; err != nil {
+		t.Fatalf("failed to query with cte, got error %v", err)
+	}
+
+	if len(results) != 2 || results[0].Name != "with_2" || results[1].Name != "with_3" {
+		t.Errorf("should find 2 users with cte, got %+v", results)
+	}
+
+	var names []string
+	if err := DB.With("with_names", DB.Model(&User{}).Select("name").Where("name LIKE ?", "with_%"), "alias").
+		Table("with_names").Order("alias").Pluck("alias", &names).Error; err != nil {
+		t.Fatalf("failed to pluck with cte columns, got error %v", err)
+	}
+
+	AssertEqual(t, names, []string{"with_1", "with_2", "with_3"})
+
+	if err := DB.With("with_young", DB.Model(&User{}).Select("id").Where("name LIKE ? AND age < ?", "with_%", 20)).
+		Model(&User{}).Where("id IN (SELECT id FROM with_young)").Update("age", 15).Error; err != nil {
+		t.Fatalf("failed to update with cte, got error %v", err)
+	}
+
+	var user User
+	DB.First(&user, users[0].ID)
+	if user.Age != 15 {
+		t.Errorf("age should be updated with cte, got %v", user.Age)
+	}
+
+	if err := DB.With("with_old", DB.Model(&User{}).Select("id").Where("name LIKE ? AND age > ?", "with_%", 20)).
+		Where("id IN (SELECT id FROM with_old)").Delete(&User{}).Error; err != nil {
+		t.Fatalf("failed to delete with cte, got error %v", err)
+	}
+
+	var count int64
+	DB.Model(&User{}).Where("name LIKE ?", "with_%").Count(&count)
+	if count != 2 {
+		t.Errorf("should delete 1 user with cte, but got %v users left", count)
+	}
+}
+
+func TestWithRecursive(t *testing.T) {
+	manager := *GetUser("with_recursive_manager", Config{Team: 2})
+	DB.Create(&manager)
+
+	member := manager.Team[0]
+	member.Team = []User{*GetUser("with_recursive_member_team", Config{})}
+	DB.Save(&member)
+
+	var results []User
+	if err := DB.WithRecursive("team_tree", DB.Raw(
+		"SELECT id, name, manager_id, deleted_at FROM users WHERE id = ? UNION ALL SELECT users.id, users.name, users.manager_id, users.deleted_at FROM users INNER JOIN team_tree ON users.manager_id = team_tree.id",
+		manager.ID,
+	), "id", "name", "manager_id", "deleted_at").Table("team_tree").Order("id").Find(&results).Error; err != nil {
+		t.Fatalf("failed to query with recursive cte, got error %v", err)
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("should find manager and all 3 members, got %v", len(results))
+	}
+
+	AssertEqual(t, results[0].Name, manager.Name)
+	AssertEqual(t, results[3].Name, "with_recursive_member_team")
+}
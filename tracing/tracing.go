@@ -0,0 +1,143 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tracer starts spans, could be implemented with OpenTelemetry, OpenTracing etc
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span span of an operation
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Metrics records operations' latency and errors, could be implemented with prometheus histograms and counters
+type Metrics interface {
+	ObserveLatency(operation, table string, latency time.Duration)
+	IncErrors(operation, table string)
+}
+
+// Span attributes
+const (
+	AttributeSystem       = "db.system"
+	AttributeStatement    = "db.statement"
+	AttributeTable        = "db.table"
+	AttributeRowsAffected = "db.rows_affected"
+)
+
+// Config tracing plugin config, tracer and metrics are optional
+type Config struct {
+	Tracer  Tracer
+	Metrics Metrics
+}
+
+// Plugin tracing and metrics plugin, starts a span and records latency for every processor execution
+type Plugin struct {
+	Config
+}
+
+type execution struct {
+	ctx   context.Context
+	span  Span
+	begin time.Time
+}
+
+const executionKey = "gorm:tracing"
+
+// New create tracing plugin
+func New(config Config) *Plugin {
+	return &Plugin{Config: config}
+}
+
+// Name plugin name
+func (p *Plugin) Name() string {
+	return "gorm:tracing"
+}
+
+// Initialize register callbacks around processors' callbacks
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	var (
+		beforeCreate, beforeUpdate, beforeDelete = "gorm:before_create", "gorm:setup_reflect_value", "gorm:before_delete"
+		callback                                 = db.Callback()
+	)
+
+	if !db.SkipDefaultTransaction {
+		beforeCreate, beforeUpdate, beforeDelete = "gorm:begin_transaction", "gorm:begin_transaction", "gorm:begin_transaction"
+	}
+
+	for _, err := range []error{
+		callback.Create().Before(beforeCreate).Register("tracing:before_create", p.before("create")),
+		callback.Create().After("gorm:commit_or_rollback_transaction").Register("tracing:after_create", p.after("create")),
+		callback.Query().Before("gorm:query").Register("tracing:before_query", p.before("query")),
+		callback.Query().After("gorm:after_query").Register("tracing:after_query", p.after("query")),
+		callback.Update().Before(beforeUpdate).Register("tracing:before_update", p.before("update")),
+		callback.Update().After("gorm:commit_or_rollback_transaction").Register("tracing:after_update", p.after("update")),
+		callback.Delete().Before(beforeDelete).Register("tracing:before_delete", p.before("delete")),
+		callback.Delete().After("gorm:commit_or_rollback_transaction").Register("tracing:after_delete", p.after("delete")),
+		callback.Row().Before("gorm:raw").Register("tracing:before_row", p.before("row")),
+		callback.Row().After("gorm:raw").Register("tracing:after_row", p.after("row")),
+		callback.Raw().Before("gorm:raw").Register("tracing:before_raw", p.before("raw")),
+		callback.Raw().After("gorm:raw").Register("tracing:after_raw", p.after("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Plugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		exec := &execution{ctx: db.Statement.Context, begin: time.Now()}
+		if p.Tracer != nil {
+			ctx := db.Statement.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			// operations executed in callbacks, e.g: saving associations, will be children of the span
+			db.Statement.Context, exec.span = p.Tracer.Start(ctx, "gorm."+operation)
+		}
+		db.InstanceSet(executionKey, exec)
+	}
+}
+
+func (p *Plugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(executionKey)
+		if !ok {
+			return
+		}
+
+		exec := v.(*execution)
+		db.Statement.Context = exec.ctx
+
+		hasError := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+		if exec.span != nil {
+			exec.span.SetAttribute(AttributeSystem, db.Dialector.Name())
+			exec.span.SetAttribute(AttributeStatement, db.Statement.SQL.String())
+			exec.span.SetAttribute(AttributeTable, db.Statement.Table)
+			exec.span.SetAttribute(AttributeRowsAffected, db.RowsAffected)
+			if hasError {
+				exec.span.RecordError(db.Error)
+			}
+			exec.span.End()
+		}
+
+		if p.Metrics != nil {
+			p.Metrics.ObserveLatency(operation, db.Statement.Table, time.Since(exec.begin))
+			if hasError {
+				p.Metrics.IncErrors(operation, db.Statement.Table)
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package gorm
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Propagation decides how TransactionWithPropagation works with the current transaction
+type Propagation int
+
+const (
+	// PropagationNested run in a savepoint of the current transaction, or begin a new transaction if there is none
+	PropagationNested Propagation = iota
+	// PropagationRequired join the current transaction, or begin a new transaction if there is none
+	PropagationRequired
+	// PropagationRequiresNew always begin a new transaction with another connection, the current transaction is left untouched
+	PropagationRequiresNew
+	// PropagationMandatory join the current transaction, returns ErrMissingTransaction if there is none
+	PropagationMandatory
+	// PropagationNever run without transaction, returns ErrExistingTransaction if there is one
+	PropagationNever
+)
+
+// savePointSeq sequence of savepoints for transactions not started by Begin
+var savePointSeq uint64
+
+// txConnPool connection pool of a started transaction, it queues callbacks registered with AfterCommit, AfterRollback
+// and runs them once the transaction is committed or rolled back
+type txConnPool struct {
+	ConnPool
+	mux            sync.Mutex
+	afterCommits   []func()
+	afterRollbacks []func()
+	savePoints     map[string][2]int
+	savePointSeq   uint64
+}
+
+// Unwrap returns the wrapped conn pool of the transaction, e.g: *sql.Tx
+func (pool *txConnPool) Unwrap() ConnPool {
+	return pool.ConnPool
+}
+
+func (pool *txConnPool) Commit() error {
+	err := pool.ConnPool.(TxCommitter).Commit()
+	if err == nil {
+		pool.run(pool.takeCallbacks(true))
+	} else {
+		pool.run(pool.takeCallbacks(false))
+	}
+	return err
+}
+
+func (pool *txConnPool) Rollback() error {
+	err := pool.ConnPool.(TxCommitter).Rollback()
+	pool.run(pool.takeCallbacks(false))
+	return err
+}
+
+// takeCallbacks returns callbacks for committed or rolled back transaction, and resets queued callbacks so they will only run once
+func (pool *txConnPool) takeCallbacks(committed bool) (fcs []func()) {
+	pool.mux.Lock()
+	if committed {
+		fcs = pool.afterCommits
+	} else {
+		fcs = pool.afterRollbacks
+	}
+	pool.afterCommits, pool.afterRollbacks, pool.savePoints = nil, nil, nil
+	pool.mux.Unlock()
+	return
+}
+
+func (pool *txConnPool) run(fcs []func()) {
+	for _, fc := range fcs {
+		fc()
+	}
+}
+
+func (pool *txConnPool) savePoint(name string) {
+	pool.mux.Lock()
+	if pool.savePoints == nil {
+		pool.savePoints = map[string][2]int{}
+	}
+	pool.savePoints[name] = [2]int{len(pool.afterCommits), len(pool.afterRollbacks)}
+	pool.mux.Unlock()
+}
+
+// rollbackTo discards callbacks registered after the save point
+func (pool *txConnPool) rollbackTo(name string) {
+	pool.mux.Lock()
+	if marks, ok := pool.savePoints[name]; ok {
+		if marks[0] <= len(pool.afterCommits) {
+			pool.afterCommits = pool.afterCommits[:marks[0]]
+		}
+		if marks[1] <= len(pool.afterRollbacks) {
+			pool.afterRollbacks = pool.afterRollbacks[:marks[1]]
+		}
+	}
+	pool.mux.Unlock()
+}
+
+// nextSavePointName returns unique and monotonic savepoint name for the transaction
+func nextSavePointName(connPool ConnPool) string {
+	if pool, ok := connPool.(*txConnPool); ok {
+		return "sp" + strconv.FormatUint(atomic.AddUint64(&pool.savePointSeq, 1), 10)
+	}
+	return "sp" + strconv.FormatUint(atomic.AddUint64(&savePointSeq, 1), 10)
+}
+
+// AfterCommit register fc to be run after the outermost transaction committed
+func (db *DB) AfterCommit(fc func()) *DB {
+	tx := db.getInstance()
+	if pool, ok := tx.Statement.ConnPool.(*txConnPool); ok {
+		pool.mux.Lock()
+		pool.afterCommits = append(pool.afterCommits, fc)
+		pool.mux.Unlock()
+	} else {
+		tx.AddError(ErrInvalidTransaction)
+	}
+	return tx
+}
+
+// AfterRollback register fc to be run after the outermost transaction rolled back
+func (db *DB) AfterRollback(fc func()) *DB {
+	tx := db.getInstance()
+	if pool, ok := tx.Statement.ConnPool.(*txConnPool); ok {
+		pool.mux.Lock()
+		pool.afterRollbacks = append(pool.afterRollbacks, fc)
+		pool.mux.Unlock()
+	} else {
+		tx.AddError(ErrInvalidTransaction)
+	}
+	return tx
+}
+
+// InTransaction returns true if db is in a transaction started by Begin
+func (db *DB) InTransaction() bool {
+	_, ok := db.Statement.ConnPool.(*txConnPool)
+	return ok
+}
@@ -0,0 +1,129 @@
+package gorm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Version optimistic lock version, updating a record with outdated version returns ErrStaleObject
+type Version sql.NullInt64
+
+// Scan implements the Scanner interface.
+func (v *Version) Scan(value interface{}) error {
+	return (*sql.NullInt64)(v).Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (v Version) Value() (driver.Value, error) {
+	if !v.Valid {
+		return nil, nil
+	}
+	return v.Int64, nil
+}
+
+func (Version) CreateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{VersionCreateClause{Field: f}}
+}
+
+func (Version) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{VersionUpdateClause{Field: f}}
+}
+
+// VersionCreateClause initialize version to 1 when creating
+type VersionCreateClause struct {
+	Field *schema.Field
+}
+
+func (VersionCreateClause) Name() string {
+	return ""
+}
+
+func (VersionCreateClause) Build(clause.Builder) {
+}
+
+func (VersionCreateClause) MergeClause(*clause.Clause) {
+}
+
+func (vc VersionCreateClause) ModifyStatement(stmt *Statement) {
+	if stmt.SQL.String() == "" {
+		switch stmt.ReflectValue.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < stmt.ReflectValue.Len(); i++ {
+				if _, isZero := vc.Field.ValueOf(stmt.ReflectValue.Index(i)); isZero {
+					vc.Field.Set(stmt.ReflectValue.Index(i), 1)
+				}
+			}
+		case reflect.Struct:
+			if _, isZero := vc.Field.ValueOf(stmt.ReflectValue); isZero {
+				vc.Field.Set(stmt.ReflectValue, 1)
+			}
+		}
+	}
+}
+
+// VersionUpdateClause check current version and increase it when updating, e.g: SET `version`=`version`+1 WHERE `version` = ?
+type VersionUpdateClause struct {
+	Field *schema.Field
+}
+
+func (VersionUpdateClause) Name() string {
+	return ""
+}
+
+func (VersionUpdateClause) Build(clause.Builder) {
+}
+
+func (VersionUpdateClause) MergeClause(*clause.Clause) {
+}
+
+func (vc VersionUpdateClause) ModifyStatement(stmt *Statement) {
+	if stmt.SQL.String() == "" {
+		column := clause.Column{Name: vc.Field.DBName}
+		increment := clause.Assignment{
+			Column: column,
+			Value:  clause.Expr{SQL: stmt.Quote(column) + "+1"},
+		}
+
+		var set clause.Set
+		if c, ok := stmt.Clauses["SET"]; ok {
+			set, _ = c.Expression.(clause.Set)
+		}
+
+		var assigned bool
+		for idx, assignment := range set {
+			if assignment.Column.Name == vc.Field.DBName {
+				set[idx], assigned = increment, true
+			}
+		}
+
+		if !assigned {
+			set = append(set, increment)
+		}
+		stmt.AddClause(set)
+
+		if stmt.ReflectValue.Kind() == reflect.Struct {
+			if value, isZero := vc.Field.ValueOf(stmt.ReflectValue); !isZero {
+				stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+					clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: vc.Field.DBName}, Value: value},
+				}})
+				stmt.DB.InstanceSet("gorm:version_field", vc.Field)
+			}
+		}
+	}
+}
+
+// versionField returns the version field of the schema, returns nil if not found
+func versionField(s *schema.Schema) *schema.Field {
+	if s != nil {
+		for _, c := range s.UpdateClauses {
+			if vc, ok := c.(VersionUpdateClause); ok {
+				return vc.Field
+			}
+		}
+	}
+	return nil
+}